@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lasergit/internal/git"
+	"lasergit/internal/gitea"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"github.com/spf13/cobra"
+)
+
+var checkoutCmd = &cobra.Command{
+	Use:   "checkout <number>",
+	Short: "Checkout a pull request as agit-<number>",
+	Long: `Fetch the head of a pull request into the local branch agit-<number> and
+check it out, without opening the interactive list.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeOpenPRs,
+	RunE:              runCheckout,
+}
+
+func init() {
+	rootCmd.AddCommand(checkoutCmd)
+}
+
+func runCheckout(cmd *cobra.Command, args []string) error {
+	index, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	pr, err := rc.client.GetPullRequest(rc.owner, rc.repoName, index)
+	if err != nil {
+		return fmt.Errorf("failed to get PR #%d: %w", index, err)
+	}
+
+	return checkoutPR(rc.repo, pr)
+}
+
+// checkoutPR fetches the head of pr into agit-<index>, checks it out and
+// reports the result.
+func checkoutPR(repo *git.Repository, pr *sdk.PullRequest) error {
+	fmt.Printf("🔄 Fetching and checking out PR #%d...\n", pr.Index)
+	branchName, err := checkoutPRBranch(repo, pr)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Successfully checked out PR #%d: %s\n", pr.Index, pr.Title)
+	fmt.Printf("📍 You are now on branch '%s'\n", branchName)
+	return nil
+}
+
+// checkoutPRBranch fetches the head of pr into agit-<index> and checks it
+// out, returning the branch name.
+func checkoutPRBranch(repo *git.Repository, pr *sdk.PullRequest) (string, error) {
+	branchName := git.PRBranchName(pr.Index)
+
+	err := repo.FetchPullRequest("origin", int(pr.Index), branchName)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch PR: %w", err)
+	}
+
+	err = repo.CheckoutBranch(branchName)
+	if err != nil {
+		return "", fmt.Errorf("failed to checkout branch: %w", err)
+	}
+
+	return branchName, nil
+}
+
+// parsePRNumber accepts "42" as well as "#42".
+func parsePRNumber(arg string) (int64, error) {
+	index, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+	if err != nil || index <= 0 {
+		return 0, fmt.Errorf("invalid pull request number: %s", arg)
+	}
+
+	return index, nil
+}
+
+// completeOpenPRs completes the numbers of open pull requests, using the
+// title as the completion description.
+func completeOpenPRs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	prs, err := rc.client.ListPullRequests(rc.owner, rc.repoName, gitea.PRFilter{State: gitea.PRStateOpen})
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		completions = append(completions, fmt.Sprintf("%d\t%s", pr.Index, pr.Title))
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
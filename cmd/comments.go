@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"lasergit/internal/diff"
+	"lasergit/internal/git"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commentsFormat   string
+	commentsResolved bool
+)
+
+var commentsCmd = &cobra.Command{
+	Use:   "comments <number>",
+	Short: "Print the review comments of a pull request for editors",
+	Long: `Print the unresolved review comments of a pull request, one per line as
+path:line:col: author: text
+
+Line numbers are mapped through the changes made since each comment was
+written, so they match the local agit-<number> branch (or the head of the
+pull request if that branch doesn't exist). Load the output into the quickfix
+list of vim with:
+
+  :cexpr system('lasergit comments 42')
+
+Use --format json to get the comments with their full text instead.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeOpenPRs,
+	RunE:              runComments,
+}
+
+func init() {
+	commentsCmd.Flags().StringVar(&commentsFormat, "format", "quickfix", "Output format: quickfix or json")
+	commentsCmd.Flags().BoolVar(&commentsResolved, "resolved", false, "Include comments of resolved conversations")
+	commentsCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]string{"quickfix", "json"}, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.AddCommand(commentsCmd)
+}
+
+// commentLocation is a review comment at its position in the local branch.
+type commentLocation struct {
+	Path    string    `json:"path"`
+	Line    int       `json:"line"`
+	Column  int       `json:"column"`
+	Author  string    `json:"author"`
+	Body    string    `json:"body"`
+	URL     string    `json:"url,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+func runComments(cmd *cobra.Command, args []string) error {
+	if commentsFormat != "quickfix" && commentsFormat != "json" {
+		return fmt.Errorf("invalid format %q, must be quickfix or json", commentsFormat)
+	}
+
+	index, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	pr, err := rc.client.GetPullRequest(rc.owner, rc.repoName, index)
+	if err != nil {
+		return fmt.Errorf("failed to get PR #%d: %w", index, err)
+	}
+
+	reviews, err := rc.client.ListPullReviews(rc.owner, rc.repoName, index)
+	if err != nil {
+		return fmt.Errorf("failed to list reviews: %w", err)
+	}
+
+	comments, err := rc.client.ListReviewComments(rc.owner, rc.repoName, index, reviews)
+	if err != nil {
+		return fmt.Errorf("failed to list review comments: %w", err)
+	}
+
+	if !commentsResolved {
+		comments = unresolvedComments(comments)
+	}
+
+	locations, err := mapCommentLocations(rc, pr, comments)
+	if err != nil {
+		return err
+	}
+
+	if commentsFormat == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(locations)
+	}
+
+	for _, loc := range locations {
+		// Quickfix entries are single lines
+		text := strings.Join(strings.Fields(loc.Body), " ")
+		fmt.Printf("%s:%d:%d: %s: %s\n", loc.Path, loc.Line, loc.Column, loc.Author, text)
+	}
+	return nil
+}
+
+// unresolvedComments drops the comments of resolved conversations. Gitea
+// groups code comments into conversations by file and line.
+func unresolvedComments(comments []*sdk.PullReviewComment) []*sdk.PullReviewComment {
+	type key struct {
+		path    string
+		oldLine uint64
+		newLine uint64
+	}
+
+	resolved := make(map[key]bool)
+	for _, comment := range comments {
+		if comment.Resolver != nil {
+			resolved[key{comment.Path, comment.OldLineNum, comment.LineNum}] = true
+		}
+	}
+
+	var unresolved []*sdk.PullReviewComment
+	for _, comment := range comments {
+		if !resolved[key{comment.Path, comment.OldLineNum, comment.LineNum}] {
+			unresolved = append(unresolved, comment)
+		}
+	}
+	return unresolved
+}
+
+// mapCommentLocations places comments on the lines of the local agit-<index>
+// branch, or the head of pr if it hasn't been checked out. Comments on the
+// new version of a file are mapped through the changes since the commit
+// they were written on, comments on removed lines through the diff of the
+// pull request and then the changes since its head. Where that isn't
+// possible the original line is kept.
+func mapCommentLocations(rc *repoContext, pr *sdk.PullRequest, comments []*sdk.PullReviewComment) ([]commentLocation, error) {
+	head := ""
+	if pr.Head != nil {
+		head = pr.Head.Sha
+	}
+
+	// Compare commits, not names, to skip comments already on the target
+	target := head
+	if branch := git.PRBranchName(pr.Index); rc.repo.HasBranch(branch) {
+		sha, err := rc.repo.ResolveRevision("refs/heads/" + branch)
+		if err != nil {
+			return nil, err
+		}
+		target = sha
+	}
+
+	var prFiles []*diff.File
+	if hasOldSideComment(comments) {
+		text, err := loadPRDiff(rc, pr.Index)
+		if err != nil {
+			return nil, err
+		}
+		if prFiles, err = diff.Parse(text); err != nil {
+			return nil, fmt.Errorf("failed to parse diff: %w", err)
+		}
+	}
+
+	// Diffs from the commits comments were written on, by commit and path
+	type diffKey struct{ commit, path string }
+	changes := make(map[diffKey]*diff.File)
+	changesSince := func(commit, path string) *diff.File {
+		if commit == "" || target == "" || commit == target {
+			return nil
+		}
+
+		k := diffKey{commit, path}
+		if file, ok := changes[k]; ok {
+			return file
+		}
+
+		var file *diff.File
+		// Commits of older pushes may be gone, keep the line then
+		if text, err := rc.repo.Diff(commit, target, path); err == nil {
+			if files, err := diff.Parse(text); err == nil {
+				file = diff.FindFile(files, path)
+			}
+		}
+		changes[k] = file
+		return file
+	}
+
+	locations := make([]commentLocation, 0, len(comments))
+	for _, comment := range comments {
+		line := int(comment.LineNum)
+		commit := comment.CommitID
+		if comment.LineNum == 0 {
+			// The diff of the pull request leads to its head, which may
+			// still differ from the target
+			line = int(comment.OldLineNum)
+			commit = ""
+			if file := diff.FindFile(prFiles, comment.Path); file != nil {
+				line = file.MapLine(line)
+				commit = head
+			}
+		}
+		if file := changesSince(commit, comment.Path); file != nil {
+			line = file.MapLine(line)
+		}
+
+		author := ""
+		if comment.Reviewer != nil {
+			author = comment.Reviewer.UserName
+		}
+
+		locations = append(locations, commentLocation{
+			Path:    comment.Path,
+			Line:    max(line, 1),
+			Column:  1,
+			Author:  author,
+			Body:    comment.Body,
+			URL:     comment.HTMLURL,
+			Created: comment.Created,
+		})
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		if locations[i].Path != locations[j].Path {
+			return locations[i].Path < locations[j].Path
+		}
+		if locations[i].Line != locations[j].Line {
+			return locations[i].Line < locations[j].Line
+		}
+		return locations[i].Created.Before(locations[j].Created)
+	})
+	return locations, nil
+}
+
+func hasOldSideComment(comments []*sdk.PullReviewComment) bool {
+	for _, comment := range comments {
+		if comment.LineNum == 0 {
+			return true
+		}
+	}
+	return false
+}
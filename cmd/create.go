@@ -0,0 +1,452 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"lasergit/internal/draft"
+	"lasergit/internal/git"
+	"lasergit/internal/gitea"
+	"lasergit/internal/topic"
+	"lasergit/internal/tui"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createTitle           string
+	createDescription     string
+	createDescriptionFile string
+	createTarget          string
+	createTopic           string
+	createReviewers       string
+	createAssignees       string
+	createLabels          string
+	createMilestone       string
+	createJSON            bool
+	createEditor          bool
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a pull request using AGit",
+	Long: `Create a pull request by pushing HEAD to refs/for/<target> with AGit
+push options.
+
+All fields can be given as flags, which makes the command usable from scripts
+and git aliases. If the title is missing and stdin/stdout are attached to a
+terminal, the interactive create dialog is shown with the given values
+prefilled. With --editor the dialog opens the title and description in the
+editor git uses for commit messages first.
+
+AGit can't pass reviewers, assignees, labels and a milestone, they are set
+through the API once the pull request has been created.
+
+The number and URL of the new pull request are taken from the messages Gitea
+prints during the push, or looked up by topic if they are missing. Use --json
+to print them as JSON.`,
+	Args: cobra.NoArgs,
+	RunE: runCreate,
+}
+
+func init() {
+	createCmd.Flags().StringVarP(&createTitle, "title", "t", "", "Pull request title")
+	createCmd.Flags().StringVarP(&createDescription, "description", "d", "", "Pull request description")
+	createCmd.Flags().StringVarP(&createDescriptionFile, "description-file", "F", "", "Read the description from a file (\"-\" for stdin)")
+	createCmd.Flags().StringVar(&createTarget, "target", "", "Target branch of the pull request (defaults to the repository's default branch)")
+	createCmd.Flags().StringVar(&createTopic, "topic", "", "AGit topic (defaults to the one remembered for the branch, or one generated from the branch or title)")
+	createCmd.Flags().StringVar(&createReviewers, "reviewers", "", "Comma separated user names to request reviews from")
+	createCmd.Flags().StringVar(&createAssignees, "assignees", "", "Comma separated user names to assign")
+	createCmd.Flags().StringVar(&createLabels, "labels", "", "Comma separated label names")
+	createCmd.Flags().StringVar(&createMilestone, "milestone", "", "Milestone name")
+	createCmd.Flags().BoolVar(&createJSON, "json", false, "Print the created pull request as JSON")
+	createCmd.Flags().BoolVarP(&createEditor, "editor", "e", false, "Compose the title and description in $EDITOR")
+	createCmd.MarkFlagsMutuallyExclusive("description", "description-file")
+	rootCmd.AddCommand(createCmd)
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	description := createDescription
+	if createDescriptionFile != "" {
+		description, err = readDescriptionFile(createDescriptionFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if createTitle == "" && !isInteractive() {
+		return fmt.Errorf("--title is required when not running in a terminal")
+	}
+	if createEditor && !isInteractive() {
+		return fmt.Errorf("--editor requires a terminal")
+	}
+	showDialog := createTitle == "" || createEditor
+
+	opts, err := createPROptions(rc, createTopic, createTarget, showDialog)
+	if err != nil {
+		return err
+	}
+	opts.StartInEditor = createEditor
+	// Given values replace the ones prefilled from the commits
+	if createTitle != "" {
+		opts.Title = createTitle
+		opts.Topic = defaultTopic(opts)
+	}
+	if description != "" {
+		opts.Description = description
+		opts.Templates = nil
+	}
+	opts.Reviewers = gitea.SplitList(createReviewers)
+	opts.Assignees = gitea.SplitList(createAssignees)
+	opts.Labels = gitea.SplitList(createLabels)
+	opts.Milestone = strings.TrimSpace(createMilestone)
+
+	// The dialog shows the problems itself
+	if !showDialog {
+		if err := checkCreate(rc, opts.Topic, opts.Target); err != nil {
+			return err
+		}
+	}
+
+	result := tui.CreatePRResult{
+		Title:       opts.Title,
+		Description: opts.Description,
+		Topic:       opts.Topic,
+		Target:      opts.Target,
+		Reviewers:   opts.Reviewers,
+		Assignees:   opts.Assignees,
+		Labels:      opts.Labels,
+		Milestone:   opts.Milestone,
+	}
+
+	if showDialog {
+		dialogResult, err := tui.ShowCreatePRDialog(opts)
+		if err != nil {
+			return fmt.Errorf("failed to get PR details: %w", err)
+		}
+		result = *dialogResult
+	}
+
+	if !createJSON {
+		return pushNewPR(rc, result)
+	}
+
+	created, err := createAGitPR(rc, result)
+	if err != nil {
+		return err
+	}
+	printWarnings(created)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(created)
+}
+
+// createProblems checks whether a pull request for topicName can be created
+// against target. Blocking problems would push the wrong commits or none, or
+// update a pull request instead of creating one.
+func createProblems(rc *repoContext, topicName, target string) []tui.Problem {
+	var problems []tui.Problem
+	blocking := func(format string, args ...any) {
+		problems = append(problems, tui.Problem{Message: fmt.Sprintf(format, args...), Blocking: true})
+	}
+	warning := func(format string, args ...any) {
+		problems = append(problems, tui.Problem{Message: fmt.Sprintf(format, args...)})
+	}
+
+	switch err := topic.Validate(topicName); {
+	case err != nil:
+		blocking("%v", err)
+	case topicName == target:
+		blocking("topic '%s' is the target branch, choose another topic", topicName)
+	}
+
+	base := "origin/" + target
+	if commits, err := rc.repo.CommitsSince(base); err != nil {
+		warning("can't compare with %s: %v", base, err)
+	} else if len(commits) == 0 {
+		blocking("HEAD has no commits that aren't on %s", base)
+	}
+
+	if dirty, err := rc.repo.HasUncommittedChanges(); err == nil && dirty {
+		warning("uncommitted changes aren't part of the pull request")
+	}
+
+	pr, err := rc.client.FindPullRequestByTopic(rc.owner, rc.repoName, topicName)
+	switch {
+	case err != nil:
+		warning("can't look for an open PR for topic '%s': %v", topicName, err)
+	case pr != nil:
+		blocking("PR #%d is already open for topic '%s', use 'lasergit push' to update it", pr.Index, topicName)
+	}
+
+	return problems
+}
+
+// checkCreate prints the warnings of createProblems and fails on the first
+// blocking problem.
+func checkCreate(rc *repoContext, topicName, target string) error {
+	var blocking error
+	for _, problem := range createProblems(rc, topicName, target) {
+		if !problem.Blocking {
+			fmt.Fprintf(os.Stderr, "⚠️  %s\n", problem.Message)
+		} else if blocking == nil {
+			blocking = fmt.Errorf("can't create the pull request: %s", problem.Message)
+		}
+	}
+	return blocking
+}
+
+// createdPR is a pull request opened by an AGit push.
+type createdPR struct {
+	// Number is 0 if the pull request couldn't be found after the push
+	Number int64  `json:"number"`
+	URL    string `json:"url,omitempty"`
+	Title  string `json:"title"`
+	Topic  string `json:"topic"`
+	Target string `json:"target"`
+	// Warnings report what failed after the pull request was created
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// printWarnings reports the warnings of created on stderr.
+func printWarnings(created *createdPR) {
+	for _, warning := range created.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+}
+
+// pushNewPR pushes HEAD as a new AGit pull request and reports the result.
+func pushNewPR(rc *repoContext, result tui.CreatePRResult) error {
+	created, err := createAGitPR(rc, result)
+	if err != nil {
+		return err
+	}
+	printWarnings(created)
+
+	if created.Number == 0 {
+		fmt.Printf("✅ Successfully created PR for topic '%s' targeting '%s'\n", created.Topic, created.Target)
+		return nil
+	}
+
+	fmt.Printf("✅ Successfully created PR #%d for topic '%s' targeting '%s'\n", created.Number, created.Topic, created.Target)
+	if created.URL != "" {
+		fmt.Printf("🔗 %s\n", created.URL)
+	}
+	return nil
+}
+
+// createAGitPR pushes HEAD to refs/for/<target> with the push options that
+// make Gitea open a new pull request, then sets the metadata AGit can't
+// pass. Once the push succeeded the pull request is returned, with warnings
+// for the steps that failed afterwards.
+func createAGitPR(rc *repoContext, result tui.CreatePRResult) (*createdPR, error) {
+	if err := gitea.CheckPushOption("title", result.Title); err != nil {
+		return nil, err
+	}
+	if err := gitea.CheckPushOption("description", result.Description); err != nil {
+		return nil, err
+	}
+
+	pushOptions := []string{
+		fmt.Sprintf("topic=%s", result.Topic),
+		gitea.PushOption("title", result.Title),
+		gitea.PushOption("description", result.Description),
+	}
+
+	output, err := rc.repo.PushAGit(result.Target, pushOptions)
+	if err != nil {
+		if saveDraft(rc, result) == nil {
+			return nil, fmt.Errorf("failed to push, saved the pull request as draft: %w", err)
+		}
+		return nil, fmt.Errorf("failed to push: %w", err)
+	}
+
+	created := &createdPR{
+		Title:  result.Title,
+		Topic:  result.Topic,
+		Target: result.Target,
+	}
+	if err := rememberTopic(rc, result.Topic); err != nil {
+		created.Warnings = append(created.Warnings, fmt.Sprintf("failed to remember topic '%s' for the current branch: %v", result.Topic, err))
+	}
+
+	pushed := gitea.ParsePushOutput(output)
+	created.Number, created.URL = pushed.Index, pushed.URL
+
+	// The pull request was created either way, a failed lookup only leaves
+	// its number unknown
+	var lookupErr error
+	if created.Number == 0 {
+		var pr *sdk.PullRequest
+		pr, lookupErr = rc.client.FindPullRequestByTopic(rc.owner, rc.repoName, result.Topic)
+		if pr != nil {
+			created.Number, created.URL = pr.Index, pr.HTMLURL
+		}
+	}
+
+	hasMetadata := len(result.Reviewers) > 0 || len(result.Assignees) > 0 || len(result.Labels) > 0 || result.Milestone != ""
+	switch {
+	case !hasMetadata:
+	case lookupErr != nil:
+		created.Warnings = append(created.Warnings, fmt.Sprintf("failed to look up the PR for topic '%s', reviewers, assignees, labels and milestone weren't applied: %v", result.Topic, lookupErr))
+	case created.Number == 0:
+		created.Warnings = append(created.Warnings, fmt.Sprintf("no PR found for topic '%s' after pushing, reviewers, assignees, labels and milestone weren't applied", result.Topic))
+	default:
+		if err := applyPRMetadata(rc, created.Number, result); err != nil {
+			created.Warnings = append(created.Warnings, err.Error())
+		}
+	}
+
+	// The pull request exists now, a leftover draft would only be offered
+	// again by mistake, and later pushes from the branch update it
+	if err := deleteDraft(rc); err != nil {
+		created.Warnings = append(created.Warnings, fmt.Sprintf("%v, it will be offered again for the current branch", err))
+	}
+
+	return created, nil
+}
+
+// saveDraft keeps result as draft for the current branch, to be restored
+// the next time the create dialog opens on it, whatever topic it suggests
+// then.
+func saveDraft(rc *repoContext, result tui.CreatePRResult) error {
+	branch, err := checkedOutBranch(rc)
+	if err != nil {
+		return err
+	}
+
+	return draft.Save(&draft.Draft{
+		Repository:  rc.repo.Path(),
+		Branch:      branch,
+		Topic:       result.Topic,
+		Title:       result.Title,
+		Description: result.Description,
+		Target:      result.Target,
+		Reviewers:   result.Reviewers,
+		Assignees:   result.Assignees,
+		Labels:      result.Labels,
+		Milestone:   result.Milestone,
+	})
+}
+
+// deleteDraft removes the draft of the current branch.
+func deleteDraft(rc *repoContext) error {
+	branch, err := checkedOutBranch(rc)
+	if err != nil {
+		return err
+	}
+	return draft.Delete(rc.repo.Path(), branch)
+}
+
+// applyPRMetadata requests the reviews and sets the assignees, labels and
+// milestone of result on the new pull request index.
+func applyPRMetadata(rc *repoContext, index int64, result tui.CreatePRResult) error {
+	var edit gitea.PullRequestEdit
+	if len(result.Assignees) > 0 {
+		edit.Assignees = &result.Assignees
+	}
+	if len(result.Labels) > 0 {
+		edit.Labels = &result.Labels
+	}
+	if result.Milestone != "" {
+		edit.Milestone = &result.Milestone
+	}
+
+	if edit != (gitea.PullRequestEdit{}) {
+		if _, err := rc.client.EditPullRequest(rc.owner, rc.repoName, index, edit); err != nil {
+			return fmt.Errorf("created PR #%d, but failed to set assignees, labels and milestone: %w", index, err)
+		}
+	}
+
+	if len(result.Reviewers) > 0 {
+		if err := rc.client.RequestReviewers(rc.owner, rc.repoName, index, result.Reviewers); err != nil {
+			return fmt.Errorf("created PR #%d, but failed to request reviewers: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+// prefillFromCommits lists commits in opts and derives the title and
+// description from them: a single commit provides both, for several the
+// title is made from the chosen topic or the branch and the description
+// lists their subjects.
+// Merge commits are left out.
+func prefillFromCommits(opts *tui.CreatePROptions, commits []*git.Commit) {
+	var subjects []string
+	var single *git.Commit
+	for _, commit := range commits {
+		if commit.IsMerge {
+			continue
+		}
+		single = commit
+		subjects = append(subjects, commit.Subject())
+		opts.Commits = append(opts.Commits, fmt.Sprintf("%.7s %s", commit.Hash, commit.Subject()))
+	}
+
+	switch len(subjects) {
+	case 0:
+	case 1:
+		opts.Title = single.Subject()
+		opts.Description = single.Body()
+	default:
+		name := opts.Branch
+		if opts.TopicChosen {
+			name = opts.Topic
+		}
+		opts.Title = topicTitle(name)
+		opts.Description = "- " + strings.Join(subjects, "\n- ")
+	}
+}
+
+// topicTitle turns a branch name like "feature/fix-login_form" into a title
+// like "Fix login form".
+func topicTitle(topic string) string {
+	if i := strings.LastIndex(topic, "/"); i >= 0 {
+		topic = topic[i+1:]
+	}
+	title := strings.Join(strings.FieldsFunc(topic, func(r rune) bool {
+		return r == '-' || r == '_'
+	}), " ")
+
+	if title == "" {
+		return topic
+	}
+	first, size := utf8.DecodeRuneInString(title)
+	return string(unicode.ToUpper(first)) + title[size:]
+}
+
+func readDescriptionFile(path string) (string, error) {
+	var data []byte
+	var err error
+
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read description: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// isInteractive reports whether both stdin and stdout are terminals, i.e.
+// whether it is safe to start a TUI.
+func isInteractive() bool {
+	return isatty.IsTerminal(os.Stdin.Fd()) && isatty.IsTerminal(os.Stdout.Fd())
+}
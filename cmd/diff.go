@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"lasergit/internal/gitea"
+	"lasergit/internal/tui"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <number>",
+	Short: "Show the changes of a pull request",
+	Long: `Show the diff of a pull request against its merge base.
+
+In a terminal the diff opens in an interactive viewer with a file tree and
+syntax highlighting:
+• Navigate with ↑/↓, pgup/pgdn, g/G
+• Press 'n'/'p' to jump to the next/previous hunk
+• Press 'N'/'P' to jump to the next/previous file
+• Press Enter or 'z' to fold a file, 'Z' to fold all files
+• Press 't' to toggle the file tree
+• Press 'c' to comment on a line, 'x' to delete a pending comment
+• Press 's' to submit the pending comments as a review
+• Press 'q' or Esc to quit
+
+Otherwise the raw diff is written to stdout.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeOpenPRs,
+	RunE:              runDiff,
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	index, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	if !isInteractive() {
+		diff, err := loadPRDiff(rc, index)
+		if err != nil {
+			return err
+		}
+
+		_, err = os.Stdout.WriteString(diff)
+		return err
+	}
+
+	pr, err := rc.client.GetPullRequest(rc.owner, rc.repoName, index)
+	if err != nil {
+		return fmt.Errorf("failed to get PR #%d: %w", index, err)
+	}
+
+	if err := tui.ShowDiff(newActions(rc, gitea.PRFilter{}), pr); err != nil {
+		return fmt.Errorf("failed to show diff: %w", err)
+	}
+
+	return nil
+}
+
+// loadPRDiff fetches the unified diff of the pull request.
+func loadPRDiff(rc *repoContext, index int64) (string, error) {
+	diff, err := rc.client.GetPullRequestDiff(rc.owner, rc.repoName, index)
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff of PR #%d: %w", index, err)
+	}
+
+	return diff, nil
+}
+
+// submitReview submits review with its line comments on the pull request.
+func submitReview(rc *repoContext, index int64, review tui.Review) error {
+	comments := make([]sdk.CreatePullReviewComment, len(review.Comments))
+	for i, comment := range review.Comments {
+		comments[i] = gitea.LineComment(comment.Path, comment.OldLine, comment.NewLine, comment.Body)
+	}
+
+	_, err := rc.client.CreatePullReview(rc.owner, rc.repoName, index, review.State, review.Body, comments)
+	if err != nil {
+		return fmt.Errorf("failed to submit review: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"lasergit/internal/gitea"
+	"lasergit/internal/tui"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editTitle           string
+	editDescription     string
+	editDescriptionFile string
+	editTarget          string
+	editAssignees       string
+	editLabels          string
+	editMilestone       string
+	editDue             string
+	editClose           bool
+	editReopen          bool
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <number>",
+	Short: "Edit, close or reopen a pull request",
+	Long: `Change the title, description, target branch or metadata of a pull
+request, or close and reopen it.
+
+Only the given flags are changed. Assignees and labels are comma separated
+lists replacing the current ones, pass an empty value to remove all of them,
+the milestone or the due date:
+
+  lasergit edit 42 --title "Fix typo" --labels bug,docs
+  lasergit edit 42 --milestone "" --due 2025-12-31
+  lasergit edit 42 --close`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeOpenPRs,
+	RunE:              runEdit,
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&editTitle, "title", "t", "", "New title")
+	editCmd.Flags().StringVarP(&editDescription, "description", "d", "", "New description")
+	editCmd.Flags().StringVarP(&editDescriptionFile, "description-file", "F", "", "Read the new description from a file (\"-\" for stdin)")
+	editCmd.Flags().StringVar(&editTarget, "target", "", "New target branch")
+	editCmd.Flags().StringVar(&editAssignees, "assignees", "", "Comma separated user names to assign")
+	editCmd.Flags().StringVar(&editLabels, "labels", "", "Comma separated label names")
+	editCmd.Flags().StringVar(&editMilestone, "milestone", "", "Milestone name")
+	editCmd.Flags().StringVar(&editDue, "due", "", "Due date as YYYY-MM-DD")
+	editCmd.Flags().BoolVar(&editClose, "close", false, "Close the pull request")
+	editCmd.Flags().BoolVar(&editReopen, "reopen", false, "Reopen the pull request")
+	editCmd.MarkFlagsMutuallyExclusive("description", "description-file")
+	editCmd.MarkFlagsMutuallyExclusive("close", "reopen")
+	rootCmd.AddCommand(editCmd)
+}
+
+func runEdit(cmd *cobra.Command, args []string) error {
+	index, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	edit, err := editFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if edit == (gitea.PullRequestEdit{}) {
+		return fmt.Errorf("nothing to change, see --help for the available flags")
+	}
+
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	pr, err := editPR(rc, index, edit)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case editClose:
+		fmt.Printf("✅ Closed PR #%d: %s\n", pr.Index, pr.Title)
+	case editReopen:
+		fmt.Printf("✅ Reopened PR #%d: %s\n", pr.Index, pr.Title)
+	default:
+		fmt.Printf("✅ Updated PR #%d: %s\n", pr.Index, pr.Title)
+	}
+	if pr.HTMLURL != "" {
+		fmt.Printf("🔗 %s\n", pr.HTMLURL)
+	}
+	return nil
+}
+
+// editFromFlags returns the changes requested by the flags that were set.
+func editFromFlags(cmd *cobra.Command) (gitea.PullRequestEdit, error) {
+	var edit gitea.PullRequestEdit
+	flags := cmd.Flags()
+
+	if flags.Changed("title") {
+		title := strings.TrimSpace(editTitle)
+		if title == "" {
+			return edit, fmt.Errorf("title must not be empty")
+		}
+		edit.Title = &title
+	}
+
+	if flags.Changed("description") {
+		edit.Body = &editDescription
+	}
+	if flags.Changed("description-file") {
+		description, err := readDescriptionFile(editDescriptionFile)
+		if err != nil {
+			return edit, err
+		}
+		edit.Body = &description
+	}
+
+	if flags.Changed("target") {
+		edit.Base = &editTarget
+	}
+
+	if flags.Changed("assignees") {
+		assignees := gitea.SplitList(editAssignees)
+		edit.Assignees = &assignees
+	}
+
+	if flags.Changed("labels") {
+		labels := gitea.SplitList(editLabels)
+		edit.Labels = &labels
+	}
+
+	if flags.Changed("milestone") {
+		milestone := strings.TrimSpace(editMilestone)
+		edit.Milestone = &milestone
+	}
+
+	if flags.Changed("due") {
+		var due time.Time
+		if editDue != "" {
+			var err error
+			if due, err = time.ParseInLocation("2006-01-02", editDue, time.Local); err != nil {
+				return edit, fmt.Errorf("invalid due date '%s', use YYYY-MM-DD", editDue)
+			}
+		}
+		edit.Deadline = &due
+	}
+
+	if editClose || editReopen {
+		state := sdk.StateClosed
+		if editReopen {
+			state = sdk.StateOpen
+		}
+		edit.State = &state
+	}
+
+	return edit, nil
+}
+
+// editPR applies edit to the pull request index and returns the result.
+func editPR(rc *repoContext, index int64, edit gitea.PullRequestEdit) (*sdk.PullRequest, error) {
+	pr, err := rc.client.EditPullRequest(rc.owner, rc.repoName, index, edit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to edit PR #%d: %w", index, err)
+	}
+
+	return pr, nil
+}
+
+// editPROptions returns what the edit dialog shows for the pull request
+// index, fetched again to edit its current state.
+func editPROptions(rc *repoContext, index int64) (tui.EditPROptions, error) {
+	pr, err := rc.client.GetPullRequest(rc.owner, rc.repoName, index)
+	if err != nil {
+		return tui.EditPROptions{}, fmt.Errorf("failed to get PR #%d: %w", index, err)
+	}
+
+	// A missing branch list only disables filtering in the picker
+	branches, _ := rc.repo.ListRemoteBranches("origin")
+
+	return tui.EditPROptions{PR: pr, Branches: branches}, nil
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"lasergit/internal/gitea"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	filterState string
+	prFilter    gitea.PRFilter
+)
+
+// addPRFilterFlags registers the flags selecting which pull requests are
+// listed.
+func addPRFilterFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&filterState, "state", "open", "Pull request state: open, closed, merged or all")
+	cmd.Flags().StringVar(&prFilter.Author, "author", "", "Only pull requests opened by this user")
+	cmd.Flags().StringVar(&prFilter.Label, "label", "", "Only pull requests with this label")
+	cmd.Flags().StringVar(&prFilter.Milestone, "milestone", "", "Only pull requests in this milestone")
+	cmd.Flags().StringVar(&prFilter.Reviewer, "reviewer", "", "Only pull requests reviewed by or awaiting review from this user (slow, fetches the reviews of every pull request)")
+	cmd.Flags().StringVar(&prFilter.Keyword, "search", "", "Only pull requests whose title or description contain this text")
+
+	cmd.RegisterFlagCompletionFunc("state", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		states := make([]string, len(gitea.PRStates))
+		for i, state := range gitea.PRStates {
+			states[i] = string(state)
+		}
+		return states, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// parsePRFilter returns the filter selected by the flags of addPRFilterFlags.
+func parsePRFilter() (gitea.PRFilter, error) {
+	state, err := gitea.ParsePRState(filterState)
+	if err != nil {
+		return gitea.PRFilter{}, err
+	}
+
+	filter := prFilter
+	filter.State = state
+	return filter, nil
+}
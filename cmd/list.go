@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"text/template"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	listJSON   bool
+	listFormat string
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List pull requests without the interactive UI",
+	Long: `List pull requests of the repository and print them to stdout.
+
+Only open pull requests are listed unless --state is given. The other filter
+flags narrow the list down further.
+
+By default a plain table is printed. Use --json to get the full pull request
+objects as a JSON array, or --format to render each pull request with a Go
+text/template, e.g.:
+
+  lasergit list --format '{{.Index}} {{.Title}} ({{.Poster.UserName}})'`,
+	Args: cobra.NoArgs,
+	RunE: runList,
+}
+
+func init() {
+	listCmd.Flags().BoolVar(&listJSON, "json", false, "Print pull requests as JSON")
+	listCmd.Flags().StringVar(&listFormat, "format", "", "Go template used to print each pull request")
+	listCmd.MarkFlagsMutuallyExclusive("json", "format")
+	addPRFilterFlags(listCmd)
+	rootCmd.AddCommand(listCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	filter, err := parsePRFilter()
+	if err != nil {
+		return err
+	}
+
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	prs, err := rc.client.ListPullRequests(rc.owner, rc.repoName, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list pull requests: %w", err)
+	}
+
+	switch {
+	case listJSON:
+		return printPRsJSON(prs)
+	case listFormat != "":
+		return printPRsTemplate(prs, listFormat)
+	default:
+		return printPRsTable(prs)
+	}
+}
+
+func printPRsJSON(prs []*gitea.PullRequest) error {
+	if prs == nil {
+		prs = []*gitea.PullRequest{}
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(prs)
+}
+
+func printPRsTemplate(prs []*gitea.PullRequest, format string) error {
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return fmt.Errorf("invalid format template: %w", err)
+	}
+
+	for _, pr := range prs {
+		if err := tmpl.Execute(os.Stdout, pr); err != nil {
+			return fmt.Errorf("failed to render PR #%d: %w", pr.Index, err)
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
+
+func printPRsTable(prs []*gitea.PullRequest) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PR\tTITLE\tAUTHOR\tTOPIC\tSTATE\tUPDATED")
+
+	for _, pr := range prs {
+		author := ""
+		if pr.Poster != nil {
+			author = pr.Poster.UserName
+		}
+
+		topic := ""
+		if pr.Head != nil {
+			topic = pr.Head.Ref
+		}
+
+		updated := ""
+		if pr.Updated != nil {
+			updated = pr.Updated.Format("2006-01-02")
+		}
+
+		state := string(pr.State)
+		if pr.HasMerged {
+			state = "merged"
+		}
+
+		fmt.Fprintf(w, "#%d\t%s\t%s\t%s\t%s\t%s\n", pr.Index, pr.Title, author, topic, state, updated)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"lasergit/internal/gitea"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeStyle        string
+	mergeTitle        string
+	mergeMessage      string
+	mergeDeleteBranch bool
+	mergeAuto         bool
+	mergeYes          bool
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <number>",
+	Short: "Merge a pull request",
+	Long: `Merge a pull request on the Gitea server.
+
+The merge style defaults to the one configured for the repository. The commit
+title and message apply to styles creating a commit (merge, rebase-merge and
+squash) and default to the ones generated by Gitea. With --auto the pull
+request is merged as soon as all status checks succeed.
+
+In a terminal the merge has to be confirmed unless --yes is given.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeOpenPRs,
+	RunE:              runMerge,
+}
+
+func init() {
+	mergeCmd.Flags().StringVarP(&mergeStyle, "style", "s", "", "Merge style: merge, rebase, rebase-merge, squash or fast-forward-only")
+	mergeCmd.Flags().StringVarP(&mergeTitle, "title", "t", "", "Title of the merge or squash commit")
+	mergeCmd.Flags().StringVarP(&mergeMessage, "message", "m", "", "Message of the merge or squash commit")
+	mergeCmd.Flags().BoolVarP(&mergeDeleteBranch, "delete-branch", "d", false, "Delete the head branch after merging")
+	mergeCmd.Flags().BoolVar(&mergeAuto, "auto", false, "Merge when all status checks succeed")
+	mergeCmd.Flags().BoolVarP(&mergeYes, "yes", "y", false, "Don't ask for confirmation")
+
+	mergeCmd.RegisterFlagCompletionFunc("style", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		styles := make([]string, len(gitea.MergeStyles))
+		for i, style := range gitea.MergeStyles {
+			styles[i] = string(style)
+		}
+		return styles, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	rootCmd.AddCommand(mergeCmd)
+}
+
+func runMerge(cmd *cobra.Command, args []string) error {
+	index, err := parsePRNumber(args[0])
+	if err != nil {
+		return err
+	}
+
+	var style sdk.MergeStyle
+	if mergeStyle != "" {
+		if style, err = gitea.ParseMergeStyle(mergeStyle); err != nil {
+			return err
+		}
+	}
+
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	pr, err := rc.client.GetPullRequest(rc.owner, rc.repoName, index)
+	if err != nil {
+		return fmt.Errorf("failed to get PR #%d: %w", index, err)
+	}
+	if pr.State != sdk.StateOpen {
+		return fmt.Errorf("PR #%d is not open", index)
+	}
+
+	if style == "" {
+		style = defaultMergeStyle(rc)
+	}
+
+	opts := sdk.MergePullRequestOption{
+		Style:                  style,
+		Title:                  mergeTitle,
+		Message:                mergeMessage,
+		DeleteBranchAfterMerge: mergeDeleteBranch,
+		MergeWhenChecksSucceed: mergeAuto,
+	}
+	if pr.Head != nil {
+		opts.HeadCommitId = pr.Head.Sha
+	}
+
+	if !mergeYes && isInteractive() {
+		ok, err := confirm(fmt.Sprintf("Merge PR #%d: %s using %s?", pr.Index, pr.Title, style))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("canceled by user")
+		}
+	}
+
+	fmt.Printf("🔀 Merging PR #%d...\n", pr.Index)
+	merged, err := mergePR(rc, pr, opts)
+	if err != nil {
+		return err
+	}
+
+	if merged {
+		fmt.Printf("✅ Successfully merged PR #%d: %s\n", pr.Index, pr.Title)
+	} else {
+		fmt.Printf("⏳ PR #%d will be merged when all checks succeed\n", pr.Index)
+	}
+	return nil
+}
+
+// mergeOptions returns the initial values of the merge dialog. The commit
+// title and message are left empty for Gitea to generate them.
+func mergeOptions(rc *repoContext, pr *sdk.PullRequest) sdk.MergePullRequestOption {
+	return sdk.MergePullRequestOption{
+		Style: defaultMergeStyle(rc),
+	}
+}
+
+// defaultMergeStyle returns the merge style configured for the repository,
+// falling back to a merge commit.
+func defaultMergeStyle(rc *repoContext) sdk.MergeStyle {
+	style, err := rc.client.GetDefaultMergeStyle(rc.owner, rc.repoName)
+	if err != nil {
+		return sdk.MergeStyleMerge
+	}
+	return style
+}
+
+// mergePR merges pr, explaining refusals caused by the rules of the target
+// branch.
+func mergePR(rc *repoContext, pr *sdk.PullRequest, opts sdk.MergePullRequestOption) (bool, error) {
+	merged, err := rc.client.MergePullRequest(rc.owner, rc.repoName, pr.Index, opts)
+
+	var apiErr *gitea.APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusMethodNotAllowed && pr.Base != nil {
+		return false, fmt.Errorf("%w (the protection rules of branch '%s' may require approvals or successful status checks)", err, pr.Base.Ref)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to merge PR #%d: %w", pr.Index, err)
+	}
+
+	return merged, nil
+}
+
+// confirm asks a yes/no question on the terminal, defaulting to no.
+func confirm(prompt string) (bool, error) {
+	fmt.Printf("%s [y/N] ", prompt)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
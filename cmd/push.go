@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"fmt"
+
+	"lasergit/internal/git"
+	"lasergit/internal/gitea"
+
+	sdk "code.gitea.io/sdk/gitea"
+	"github.com/spf13/cobra"
+)
+
+var pushTopic string
+
+var pushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Update the pull request of the current topic, or create one",
+	Long: `Push HEAD to the pull request belonging to the current topic.
+
+If an open pull request already exists for the topic, HEAD is force-pushed to
+refs/for/<target> with the same topic, updating that pull request. Otherwise
+the create dialog is opened, just like 'lasergit create'.
+
+When on an agit-<number> branch created by 'lasergit checkout', the topic of
+pull request <number> is used.`,
+	Args: cobra.NoArgs,
+	RunE: runPush,
+}
+
+func init() {
+	pushCmd.Flags().StringVar(&pushTopic, "topic", "", "AGit topic (defaults to the one remembered for the current branch or its name)")
+	rootCmd.AddCommand(pushCmd)
+}
+
+func runPush(cmd *cobra.Command, args []string) error {
+	rc, err := openRepoContext(rootRepoPath)
+	if err != nil {
+		return err
+	}
+
+	return pushOrCreatePR(rc, pushTopic)
+}
+
+// pushOrCreatePR updates the open pull request for topic if there is one and
+// falls back to the create dialog otherwise. An empty topic means the one of
+// the current branch.
+func pushOrCreatePR(rc *repoContext, givenTopic string) error {
+	pr, topic, err := findTopicPR(rc, givenTopic)
+	if err != nil {
+		return err
+	}
+
+	if pr == nil {
+		if topic != "" {
+			fmt.Printf("No open PR found for topic '%s', creating a new one\n", topic)
+		}
+		// Without a given topic the dialog chooses it like 'lasergit create'
+		return handleCreatePR(rc, givenTopic)
+	}
+
+	fmt.Printf("🔄 Updating PR #%d (topic '%s' → '%s')...\n", pr.Index, topic, pr.Base.Ref)
+	url, err := updateAGitPR(rc, pr, topic)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✅ Successfully updated PR #%d: %s\n", pr.Index, pr.Title)
+	if url != "" {
+		fmt.Printf("🔗 %s\n", url)
+	}
+	return nil
+}
+
+// findTopicPR returns the open pull request for topic, or nil if there is
+// none, along with the resolved topic. An empty topic means the one
+// remembered for the current branch or its name, where agit-<number>
+// branches resolve to the topic of that PR. A detached HEAD has no topic.
+func findTopicPR(rc *repoContext, topic string) (*sdk.PullRequest, string, error) {
+	var pr *sdk.PullRequest
+
+	if topic == "" {
+		currentBranch, err := checkedOutBranch(rc)
+		if err != nil {
+			return nil, "", err
+		}
+		if currentBranch == "" {
+			return nil, "", nil
+		}
+
+		topic = currentBranch
+		if remembered, _ := rc.repo.BranchTopic(currentBranch); remembered != "" {
+			topic = remembered
+		}
+
+		if index, ok := git.PRNumberFromBranch(currentBranch); ok {
+			pr, err = rc.client.GetPullRequest(rc.owner, rc.repoName, index)
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to get PR #%d: %w", index, err)
+			}
+			own, err := rc.client.IsOwnPullRequest(pr)
+			if err != nil {
+				return nil, "", err
+			}
+			if !own {
+				author := "another user"
+				if pr.Poster != nil {
+					author = pr.Poster.UserName
+				}
+				return nil, "", fmt.Errorf("PR #%d was opened by %s, AGit pushes can only update your own pull requests", index, author)
+			}
+			if pr.Head != nil {
+				topic = pr.Head.Ref
+			}
+		}
+	}
+
+	if pr == nil {
+		var err error
+		pr, err = rc.client.FindPullRequestByTopic(rc.owner, rc.repoName, topic)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to look up PR for topic '%s': %w", topic, err)
+		}
+	}
+
+	if pr == nil || pr.State != sdk.StateOpen {
+		return nil, topic, nil
+	}
+
+	if pr.Base == nil {
+		return nil, "", fmt.Errorf("PR #%d has no base branch", pr.Index)
+	}
+
+	return pr, topic, nil
+}
+
+// updateAGitPR force-pushes HEAD to the existing pull request of topic and
+// returns the URL of the pull request Gitea reported, or the one of pr if
+// there was none.
+func updateAGitPR(rc *repoContext, pr *sdk.PullRequest, topic string) (string, error) {
+	pushOptions := []string{
+		fmt.Sprintf("topic=%s", topic),
+		"force-push=true",
+	}
+
+	output, err := rc.repo.PushAGit(pr.Base.Ref, pushOptions)
+	if err != nil {
+		return "", fmt.Errorf("failed to push: %w", err)
+	}
+
+	if pushed := gitea.ParsePushOutput(output); pushed.URL != "" {
+		return pushed.URL, nil
+	}
+	return pr.HTMLURL, nil
+}
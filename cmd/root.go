@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"fmt"
+
+	"lasergit/internal/draft"
 	"lasergit/internal/git"
 	"lasergit/internal/gitea"
+	"lasergit/internal/prtemplate"
 	"lasergit/internal/tui"
-	"fmt"
 
+	sdk "code.gitea.io/sdk/gitea"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +26,12 @@ Browse, create, and checkout pull requests interactively:
 • Navigate with ↑/↓ arrows  
 • Press Enter to checkout a PR
 • Press 'c' to create a new PR
+• Press 'p' to push updates to the PR of the current branch
 • Press 'v' to view PR details
+• Press 'd' to view the diff of a PR
+• Press 'm' to merge a PR
+• Press 'e' to edit a PR and 'x' to close or reopen it
+• Press Tab or ←/→ to switch between open, closed, merged and all PRs
 • Press 'r' to refresh the list
 • Press 'q' or Esc to quit`,
 	RunE: runRoot,
@@ -33,119 +42,320 @@ func Execute() error {
 }
 
 func init() {
-	rootCmd.Flags().StringVar(&rootRepoPath, "repo", ".", "Path to git repository")
+	rootCmd.PersistentFlags().StringVar(&rootRepoPath, "repo", ".", "Path to git repository")
+	addPRFilterFlags(rootCmd)
 }
 
-func runRoot(cmd *cobra.Command, args []string) error {
-	return runPRLogic(rootRepoPath)
+// repoContext bundles the local repository with the Gitea client and the
+// owner/name of the remote it points at.
+type repoContext struct {
+	repo     *git.Repository
+	client   *gitea.Client
+	owner    string
+	repoName string
 }
 
-func runPRLogic(repoPath string) error {
+func openRepoContext(repoPath string) (*repoContext, error) {
 	repo, err := git.OpenRepository(repoPath)
 	if err != nil {
-		return fmt.Errorf("failed to open repository: %w", err)
+		return nil, fmt.Errorf("failed to open repository: %w", err)
 	}
 
 	remoteURL, err := repo.GetRemoteURL("origin")
 	if err != nil {
-		return fmt.Errorf("failed to get remote URL: %w", err)
+		return nil, fmt.Errorf("failed to get remote URL: %w", err)
 	}
 
 	owner, repoName, baseURL, err := gitea.ParseRemoteURL(remoteURL)
 	if err != nil {
-		return fmt.Errorf("failed to parse remote URL: %w", err)
+		return nil, fmt.Errorf("failed to parse remote URL: %w", err)
 	}
 
 	client, err := gitea.NewClient(baseURL)
 	if err != nil {
-		return fmt.Errorf("failed to create Gitea client: %w", err)
+		return nil, fmt.Errorf("failed to create Gitea client: %w", err)
+	}
+
+	return &repoContext{
+		repo:     repo,
+		client:   client,
+		owner:    owner,
+		repoName: repoName,
+	}, nil
+}
+
+func runRoot(cmd *cobra.Command, args []string) error {
+	return runPRLogic(rootRepoPath)
+}
+
+func runPRLogic(repoPath string) error {
+	filter, err := parsePRFilter()
+	if err != nil {
+		return err
 	}
 
-	prs, err := client.ListPullRequests(owner, repoName)
+	rc, err := openRepoContext(repoPath)
 	if err != nil {
-		return fmt.Errorf("failed to list pull requests: %w", err)
+		return err
 	}
 
-	currentBranch, err := repo.GetCurrentBranch()
+	currentBranch, err := rc.repo.GetCurrentBranch()
 	if err != nil {
 		return fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	result, err := tui.ShowPRList(prs, owner, repoName, currentBranch)
-	if err != nil {
+	// Push, fetch and checkout run in the background while the TUI owns
+	// the terminal
+	rc.repo.DisablePrompts()
+	actions := newActions(rc, filter)
+
+	if err := tui.Run(actions, string(filter.State), rc.owner, rc.repoName, currentBranch); err != nil {
 		return fmt.Errorf("failed to show PR list: %w", err)
 	}
 
-	switch result.Action {
-	case "checkout":
-		if result.SelectedPR != nil {
-			pr := result.SelectedPR
-			branchName := fmt.Sprintf("agit-%d", pr.Index)
-			
-			fmt.Printf("🔄 Fetching PR #%d...\n", pr.Index)
-			err = repo.FetchPullRequest("origin", int(pr.Index), branchName)
-			if err != nil {
-				return fmt.Errorf("failed to fetch PR: %w", err)
-			}
+	return nil
+}
 
-			fmt.Printf("🔀 Checking out branch '%s'...\n", branchName)
-			err = repo.CheckoutBranch(branchName)
-			if err != nil {
-				return fmt.Errorf("failed to checkout branch: %w", err)
-			}
+// newActions wires the TUI up to rc. The list pagers use filter with the
+// state of the selected tab.
+func newActions(rc *repoContext, filter gitea.PRFilter) tui.Actions {
+	repo := rc.repo
 
-			fmt.Printf("✅ Successfully checked out PR #%d: %s\n", pr.Index, pr.Title)
-			fmt.Printf("📍 You are now on branch '%s'\n", branchName)
-		}
-	case "view":
-		if result.SelectedPR != nil {
-			pr := result.SelectedPR
-			fmt.Printf("\n✨ PR #%d: %s\n", pr.Index, pr.Title)
-			if pr.Body != "" {
-				fmt.Printf("\nDescription:\n%s\n", pr.Body)
-			}
-			fmt.Printf("\nAuthor: %s\n", pr.Poster.UserName)
-			if pr.HTMLURL != "" {
-				fmt.Printf("URL: %s\n", pr.HTMLURL)
+	// Each state tab of the list gets its own pager with the same filter
+	source := func(state string) tui.PRPager {
+		f := filter
+		f.State = gitea.PRState(state)
+		return rc.client.NewPullRequestPager(rc.owner, rc.repoName, f)
+	}
+
+	return tui.Actions{
+		Source:        source,
+		CurrentBranch: repo.GetCurrentBranch,
+		Checkout: func(pr *sdk.PullRequest) (string, error) {
+			return checkoutPRBranch(repo, pr)
+		},
+		PrepareCreate: func() (tui.CreatePROptions, error) {
+			return createPROptions(rc, "", "", true)
+		},
+		Create: func(result tui.CreatePRResult) (tui.CreatedPR, error) {
+			created, err := createAGitPR(rc, result)
+			if err != nil {
+				return tui.CreatedPR{}, err
 			}
-			if pr.Updated != nil {
-				fmt.Printf("Updated: %s\n", pr.Updated.Format("2006-01-02 15:04"))
+			return tui.CreatedPR{Number: created.Number, URL: created.URL, Warnings: created.Warnings}, nil
+		},
+		FindTopicPR: func() (*sdk.PullRequest, string, error) {
+			return findTopicPR(rc, "")
+		},
+		Update: func(pr *sdk.PullRequest, topic string) (string, error) {
+			return updateAGitPR(rc, pr, topic)
+		},
+		LoadDetails: func(pr *sdk.PullRequest) (*tui.PRDetails, error) {
+			return loadPRDetails(rc, pr.Index)
+		},
+		LoadDiff: func(pr *sdk.PullRequest) (string, error) {
+			return loadPRDiff(rc, pr.Index)
+		},
+		SubmitReview: func(pr *sdk.PullRequest, review tui.Review) error {
+			return submitReview(rc, pr.Index, review)
+		},
+		ReplyToThread: func(pr *sdk.PullRequest, comment tui.ReviewComment) error {
+			// The Gitea API has no endpoint to reply to a review thread, code
+			// comments can only be created as part of a review. Each reply
+			// therefore shows up as its own single comment review.
+			return submitReview(rc, pr.Index, tui.Review{State: sdk.ReviewStateComment, Comments: []tui.ReviewComment{comment}})
+		},
+		Comment: func(pr *sdk.PullRequest, body string) error {
+			if _, err := rc.client.CreateIssueComment(rc.owner, rc.repoName, pr.Index, body); err != nil {
+				return fmt.Errorf("failed to add comment: %w", err)
 			}
-		}
-	case "create":
-		return handleCreatePR(repo)
-	case "refresh":
-		return runPRLogic(repoPath)
+			return nil
+		},
+		PrepareMerge: func(pr *sdk.PullRequest) (sdk.MergePullRequestOption, error) {
+			return mergeOptions(rc, pr), nil
+		},
+		Merge: func(pr *sdk.PullRequest, opts sdk.MergePullRequestOption) (bool, error) {
+			return mergePR(rc, pr, opts)
+		},
+		PrepareEdit: func(pr *sdk.PullRequest) (tui.EditPROptions, error) {
+			return editPROptions(rc, pr.Index)
+		},
+		Edit: func(pr *sdk.PullRequest, edit gitea.PullRequestEdit) (*sdk.PullRequest, error) {
+			return editPR(rc, pr.Index, edit)
+		},
 	}
-
-	return nil
 }
 
-func handleCreatePR(repo *git.Repository) error {
-	currentBranch, err := repo.GetCurrentBranch()
+// loadPRDetails fetches the pull request with its commits, reviews and
+// comments.
+func loadPRDetails(rc *repoContext, index int64) (*tui.PRDetails, error) {
+	pr, err := rc.client.GetPullRequest(rc.owner, rc.repoName, index)
 	if err != nil {
-		return fmt.Errorf("failed to get current branch: %w", err)
+		return nil, fmt.Errorf("failed to get PR #%d: %w", index, err)
 	}
 
-	targetBranch := "main" // Default target branch
-	topicName := currentBranch
+	commits, err := rc.client.ListPullRequestCommits(rc.owner, rc.repoName, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+
+	reviews, err := rc.client.ListPullReviews(rc.owner, rc.repoName, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
 
-	result, err := tui.ShowCreatePRDialog(topicName, targetBranch)
+	reviewComments, err := rc.client.ListReviewComments(rc.owner, rc.repoName, index, reviews)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review comments: %w", err)
+	}
+
+	comments, err := rc.client.ListIssueComments(rc.owner, rc.repoName, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+
+	return &tui.PRDetails{
+		PR:                 pr,
+		Commits:            commits,
+		RequestedReviewers: gitea.RequestedReviewers(reviews),
+		Reviews:            reviews,
+		ReviewComments:     reviewComments,
+		Comments:           comments,
+	}, nil
+}
+
+// handleCreatePR shows the create dialog for topicName, defaulting to the
+// current branch, and pushes the result.
+func handleCreatePR(rc *repoContext, topicName string) error {
+	opts, err := createPROptions(rc, topicName, "", true)
+	if err != nil {
+		return err
+	}
+
+	result, err := tui.ShowCreatePRDialog(opts)
 	if err != nil {
 		return fmt.Errorf("failed to get PR details: %w", err)
 	}
 
-	pushOptions := []string{
-		fmt.Sprintf("topic=%s", result.Topic),
-		fmt.Sprintf("title=%s", result.Title),
-		fmt.Sprintf("description=%s", result.Description),
+	return pushNewPR(rc, *result)
+}
+
+// createPROptions returns the initial values of the create dialog for
+// topicName and targetName, defaulting to the current branch and the default
+// branch. For the interactive dialog the choices of the pickers are fetched
+// and the title and description are prefilled from the commits to be pushed.
+func createPROptions(rc *repoContext, topicName, targetName string, interactive bool) (tui.CreatePROptions, error) {
+	branch, err := checkedOutBranch(rc)
+	if err != nil {
+		return tui.CreatePROptions{}, err
 	}
 
-	err = repo.PushAGit(result.Target, pushOptions)
+	rules, err := topicRules(rc)
 	if err != nil {
-		return fmt.Errorf("failed to push: %w", err)
+		return tui.CreatePROptions{}, err
 	}
 
-	fmt.Printf("✅ Successfully created PR for topic '%s' targeting '%s'\n", result.Topic, result.Target)
-	return nil
-}
\ No newline at end of file
+	// A given or remembered topic is kept, otherwise it is generated
+	if topicName == "" && branch != "" {
+		topicName, _ = rc.repo.BranchTopic(branch)
+	}
+
+	if targetName == "" {
+		targetName = defaultTargetBranch(rc)
+	}
+
+	opts := tui.CreatePROptions{
+		Topic:       topicName,
+		TopicChosen: topicName != "",
+		TopicRules:  rules,
+		Branch:      branch,
+		Target:      targetName,
+	}
+	if !interactive {
+		opts.Topic = defaultTopic(opts)
+		return opts, nil
+	}
+
+	// Without the remote-tracking branch there is just nothing to prefill
+	base := "origin/" + targetName
+	if commits, err := rc.repo.CommitsSince(base); err == nil {
+		prefillFromCommits(&opts, commits)
+	}
+	if stats, err := rc.repo.DiffStat(base); err == nil {
+		for _, stat := range stats {
+			opts.Files = append(opts.Files, tui.FileStat(stat))
+		}
+	}
+	opts.Topic = defaultTopic(opts)
+
+	opts.Validate = func(topic, target string) []tui.Problem {
+		return createProblems(rc, topic, target)
+	}
+
+	// An unreadable draft is replaced by the next one saved
+	if saved, _ := draft.Load(rc.repo.Path(), branch); saved != nil {
+		opts.Draft = &tui.CreatePRResult{
+			Title:       saved.Title,
+			Description: saved.Description,
+			Topic:       saved.Topic,
+			Target:      saved.Target,
+			Reviewers:   saved.Reviewers,
+			Assignees:   saved.Assignees,
+			Labels:      saved.Labels,
+			Milestone:   saved.Milestone,
+		}
+		opts.DraftSaved = saved.Saved
+	}
+	opts.SaveDraft = func(result tui.CreatePRResult) {
+		// Failing to keep a draft mustn't keep the dialog from closing
+		saveDraft(rc, result)
+	}
+
+	// Without an editor ctrl+o explains how to configure one
+	opts.Editor, _ = rc.repo.Editor()
+
+	// Unreadable templates are skipped like missing ones
+	templates, _ := prtemplate.Find(rc.repo.Path())
+	for _, t := range templates {
+		opts.Templates = append(opts.Templates, tui.PRTemplate{
+			Name:   t.Name,
+			About:  t.About,
+			Title:  t.Title,
+			Labels: t.Labels,
+			Body:   t.Body,
+		})
+	}
+
+	// Missing lists only leave the pickers empty, names can still be typed
+	opts.Branches, _ = rc.repo.ListRemoteBranches("origin")
+	opts.ReviewerChoices, _ = rc.client.ListReviewers(rc.owner, rc.repoName)
+	opts.AssigneeChoices, _ = rc.client.ListAssignees(rc.owner, rc.repoName)
+
+	if labels, err := rc.client.ListLabels(rc.owner, rc.repoName); err == nil {
+		for _, label := range labels {
+			opts.LabelChoices = append(opts.LabelChoices, label.Name)
+		}
+	}
+
+	if milestones, err := rc.client.ListMilestones(rc.owner, rc.repoName); err == nil {
+		for _, milestone := range milestones {
+			opts.MilestoneChoices = append(opts.MilestoneChoices, milestone.Title)
+		}
+	}
+
+	return opts, nil
+}
+
+// defaultTargetBranch returns the default branch of the Gitea repository,
+// falling back to the branch origin/HEAD points to and finally "main".
+func defaultTargetBranch(rc *repoContext) string {
+	if branch, err := rc.client.GetDefaultBranch(rc.owner, rc.repoName); err == nil {
+		return branch
+	}
+
+	if branch, err := rc.repo.GetRemoteHead("origin"); err == nil {
+		return branch
+	}
+
+	return "main"
+}
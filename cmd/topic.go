@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"lasergit/internal/topic"
+	"lasergit/internal/tui"
+)
+
+// checkedOutBranch returns the current branch, or "" for a detached HEAD.
+func checkedOutBranch(rc *repoContext) (string, error) {
+	branch, err := rc.repo.GetCurrentBranch()
+	if err != nil {
+		return "", fmt.Errorf("failed to get current branch: %w", err)
+	}
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// topicRules reads the rules for generated topics from the git config:
+//
+//	lasergit.topicFrom       "branch" (default) or "title"
+//	lasergit.topicPrefix     put in front of generated topics
+//	lasergit.topicMaxLength  longest generated topic, 50 by default
+func topicRules(rc *repoContext) (topic.Rules, error) {
+	var rules topic.Rules
+
+	from, err := rc.repo.ConfigValue("lasergit.topicFrom")
+	if err != nil {
+		return rules, err
+	}
+	switch from {
+	case "", "branch":
+	case "title":
+		rules.FromTitle = true
+	default:
+		return rules, fmt.Errorf("invalid lasergit.topicFrom '%s', must be branch or title", from)
+	}
+
+	if rules.Prefix, err = rc.repo.ConfigValue("lasergit.topicPrefix"); err != nil {
+		return rules, err
+	}
+
+	maxLength, err := rc.repo.ConfigValue("lasergit.topicMaxLength")
+	if err != nil {
+		return rules, err
+	}
+	if maxLength != "" {
+		if rules.MaxLength, err = strconv.Atoi(maxLength); err != nil || rules.MaxLength <= len(rules.Prefix) {
+			return rules, fmt.Errorf("invalid lasergit.topicMaxLength '%s', must be longer than the prefix", maxLength)
+		}
+	}
+
+	return rules, nil
+}
+
+// defaultTopic returns the topic of opts, generated from the title or
+// branch unless it was chosen.
+func defaultTopic(opts tui.CreatePROptions) string {
+	if opts.TopicChosen {
+		return opts.Topic
+	}
+	return topic.Generate(opts.TopicRules, opts.Branch, opts.Title)
+}
+
+// rememberTopic stores topic in the config of the current branch, so later
+// pushes from it update the same pull request.
+func rememberTopic(rc *repoContext, topic string) error {
+	branch, err := checkedOutBranch(rc)
+	if err != nil || branch == "" {
+		return err
+	}
+	return rc.repo.SetBranchTopic(branch, topic)
+}
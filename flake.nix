@@ -33,7 +33,7 @@
             pname = "lasergit";
             inherit version;
             src = ./.;
-            vendorHash = "sha256-A9E7Qilo/pwiFP2xBSBZYyfMtGxNKAeXEOw8Cweornc=";
+            vendorHash = "sha256-osA8eakhnvzO+Q7yyVjNtM7wbHO+Y6r/r11B7+YQ0IM=";
           };
         });
     };
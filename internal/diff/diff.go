@@ -0,0 +1,289 @@
+// Package diff parses unified diffs as produced by git and Gitea.
+package diff
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type LineKind int
+
+const (
+	Context LineKind = iota
+	Added
+	Removed
+)
+
+// Line is a single line of a hunk. OldLine and NewLine are the line numbers
+// in the old and new version of the file, 0 where the line doesn't exist.
+type Line struct {
+	Kind    LineKind
+	Content string
+	OldLine int
+	NewLine int
+}
+
+type Hunk struct {
+	Header   string
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []Line
+}
+
+type File struct {
+	OldName  string
+	NewName  string
+	IsNew    bool
+	IsDelete bool
+	IsBinary bool
+	Hunks    []Hunk
+}
+
+// Name returns the path of the file, using the old path for deleted files.
+func (f *File) Name() string {
+	if f.IsDelete || f.NewName == "" {
+		return f.OldName
+	}
+	return f.NewName
+}
+
+// Stats returns the number of added and removed lines.
+func (f *File) Stats() (added, removed int) {
+	for _, hunk := range f.Hunks {
+		for _, line := range hunk.Lines {
+			switch line.Kind {
+			case Added:
+				added++
+			case Removed:
+				removed++
+			}
+		}
+	}
+	return added, removed
+}
+
+// MapLine returns the line of the new version of the file that corresponds
+// to oldLine of the old version. Removed lines map to the first line after
+// the removal.
+func (f *File) MapLine(oldLine int) int {
+	delta := 0
+	for _, hunk := range f.Hunks {
+		// Empty sides of a hunk start after the given line
+		oldStart, newStart := hunk.OldStart, hunk.NewStart
+		if hunk.OldLines == 0 {
+			oldStart++
+		}
+		if hunk.NewLines == 0 {
+			newStart++
+		}
+		oldEnd, newEnd := oldStart+hunk.OldLines, newStart+hunk.NewLines
+
+		if oldLine < oldStart {
+			break
+		}
+		if oldLine < oldEnd {
+			return hunk.mapLine(oldLine, newEnd)
+		}
+		delta = newEnd - oldEnd
+	}
+
+	return oldLine + delta
+}
+
+// mapLine maps oldLine within the hunk, falling back to newEnd for removed
+// lines at the end of the hunk.
+func (h *Hunk) mapLine(oldLine, newEnd int) int {
+	for i, line := range h.Lines {
+		if line.OldLine != oldLine {
+			continue
+		}
+		if line.Kind == Context {
+			return line.NewLine
+		}
+		for _, next := range h.Lines[i+1:] {
+			if next.NewLine != 0 {
+				return next.NewLine
+			}
+		}
+		break
+	}
+	return newEnd
+}
+
+// FindFile returns the file with the given path in either version, or nil
+// if the diff doesn't touch it.
+func FindFile(files []*File, path string) *File {
+	for _, file := range files {
+		if file.NewName == path || file.OldName == path {
+			return file
+		}
+	}
+	return nil
+}
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse parses a multi-file unified diff in git format.
+func Parse(text string) ([]*File, error) {
+	var files []*File
+	var file *File
+	var hunk *Hunk
+	oldLine, newLine := 0, 0
+
+	flushHunk := func() {
+		if file != nil && hunk != nil {
+			file.Hunks = append(file.Hunks, *hunk)
+		}
+		hunk = nil
+	}
+
+	// inHunk reports whether the current hunk still expects lines
+	inHunk := func() bool {
+		return hunk != nil && (oldLine < hunk.OldStart+hunk.OldLines || newLine < hunk.NewStart+hunk.NewLines)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			flushHunk()
+			file = &File{}
+			files = append(files, file)
+			file.OldName, file.NewName = parseGitHeader(line)
+
+		case file == nil:
+			// Anything before the first file header, e.g. a commit message
+
+		case inHunk() && (strings.HasPrefix(line, " ") || line == ""):
+			hunk.Lines = append(hunk.Lines, Line{Kind: Context, Content: strings.TrimPrefix(line, " "), OldLine: oldLine, NewLine: newLine})
+			oldLine++
+			newLine++
+
+		case inHunk() && strings.HasPrefix(line, "+"):
+			hunk.Lines = append(hunk.Lines, Line{Kind: Added, Content: line[1:], NewLine: newLine})
+			newLine++
+
+		case inHunk() && strings.HasPrefix(line, "-"):
+			hunk.Lines = append(hunk.Lines, Line{Kind: Removed, Content: line[1:], OldLine: oldLine})
+			oldLine++
+
+		case hunk != nil && strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+
+		case strings.HasPrefix(line, "@@"):
+			flushHunk()
+			matches := hunkHeaderRegex.FindStringSubmatch(line)
+			if matches == nil {
+				return nil, fmt.Errorf("invalid hunk header: %s", line)
+			}
+			hunk = &Hunk{
+				Header:   line,
+				OldStart: atoi(matches[1]),
+				OldLines: atoiDefault(matches[2], 1),
+				NewStart: atoi(matches[3]),
+				NewLines: atoiDefault(matches[4], 1),
+			}
+			oldLine, newLine = hunk.OldStart, hunk.NewStart
+
+		case strings.HasPrefix(line, "--- "):
+			if name := stripPrefix(line[4:]); name != "" {
+				file.OldName = name
+			}
+
+		case strings.HasPrefix(line, "+++ "):
+			if name := stripPrefix(line[4:]); name != "" {
+				file.NewName = name
+			}
+
+		case strings.HasPrefix(line, "new file mode"):
+			file.IsNew = true
+
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.IsDelete = true
+
+		case strings.HasPrefix(line, "rename from "):
+			file.OldName = unquote(strings.TrimPrefix(line, "rename from "))
+
+		case strings.HasPrefix(line, "rename to "):
+			file.NewName = unquote(strings.TrimPrefix(line, "rename to "))
+
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			file.IsBinary = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flushHunk()
+	return files, nil
+}
+
+// parseGitHeader extracts the paths from "diff --git a/<old> b/<new>". The
+// result is only a fallback for the ---/+++ lines, which are unambiguous.
+func parseGitHeader(line string) (oldName, newName string) {
+	rest := strings.TrimPrefix(line, "diff --git ")
+
+	var oldPart, newPart string
+	if quoted, err := strconv.QuotedPrefix(rest); err == nil && strings.HasPrefix(rest, `"`) {
+		oldPart, newPart = quoted, strings.TrimPrefix(rest[len(quoted):], " ")
+	} else if i := strings.Index(rest, ` "b/`); i >= 0 {
+		oldPart, newPart = rest[:i], rest[i+1:]
+	} else if i := strings.Index(rest, " b/"); i >= 0 {
+		oldPart, newPart = rest[:i], rest[i+1:]
+	} else {
+		return rest, rest
+	}
+
+	return strings.TrimPrefix(unquote(oldPart), "a/"), strings.TrimPrefix(unquote(newPart), "b/")
+}
+
+// stripPrefix turns "a/path" or "b/path" into "path" and "/dev/null" into "".
+func stripPrefix(name string) string {
+	if quoted, err := strconv.QuotedPrefix(name); err == nil && strings.HasPrefix(name, `"`) {
+		name = unquote(quoted)
+	} else if i := strings.IndexByte(name, '\t'); i >= 0 {
+		// Names with spaces are followed by a tab
+		name = name[:i]
+	}
+	if name == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(name, "a/") || strings.HasPrefix(name, "b/") {
+		return name[2:]
+	}
+	return name
+}
+
+// unquote decodes paths git quoted because of special characters, like
+// "sp\303\244ce\ttab". Git uses C escapes, which Go string literals share.
+func unquote(name string) string {
+	if !strings.HasPrefix(name, `"`) {
+		return name
+	}
+	if unquoted, err := strconv.Unquote(name); err == nil {
+		return unquoted
+	}
+	return name
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+func atoiDefault(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	return atoi(s)
+}
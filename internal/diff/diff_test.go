@@ -0,0 +1,259 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type file struct {
+		oldName, newName string
+		isNew, isDelete  bool
+		isBinary         bool
+		added, removed   int
+	}
+
+	tests := []struct {
+		name string
+		text string
+		want []file
+	}{
+		{
+			name: "modified file",
+			text: `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
+-
++import "fmt"
++
+ func main() {}
+`,
+			want: []file{{oldName: "main.go", newName: "main.go", added: 2, removed: 1}},
+		},
+		{
+			name: "new and deleted files",
+			text: `diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++hello
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/old.txt
++++ /dev/null
+@@ -1,2 +0,0 @@
+-bye
+-now
+`,
+			want: []file{
+				{oldName: "new.txt", newName: "new.txt", isNew: true, added: 1},
+				{oldName: "old.txt", newName: "old.txt", isDelete: true, removed: 2},
+			},
+		},
+		{
+			name: "binary file",
+			text: `diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+`,
+			want: []file{{oldName: "logo.png", newName: "logo.png", isBinary: true}},
+		},
+		{
+			name: "names with spaces",
+			text: "diff --git a/sp ace.txt b/sp ace.txt\n" +
+				"index de98044..7be73ce 100644\n" +
+				"--- a/sp ace.txt\t\n" +
+				"+++ b/sp ace.txt\t\n" +
+				"@@ -1,3 +1,3 @@\n" +
+				" a\n" +
+				"-b\n" +
+				"+B\n" +
+				" c\n",
+			want: []file{{oldName: "sp ace.txt", newName: "sp ace.txt", added: 1, removed: 1}},
+		},
+		{
+			name: "quoted names",
+			text: `diff --git "a/tab\tname.txt" "b/tab\tname.txt"
+index 587be6b..975fbec 100644
+--- "a/tab\tname.txt"
++++ "b/tab\tname.txt"
+@@ -1 +1 @@
+-x
++y
+diff --git "a/\303\274mlaut.txt" "b/\303\274mlaut.txt"
+index 4ae8ef0..110ed9b 100644
+--- "a/\303\274mlaut.txt"
++++ "b/\303\274mlaut.txt"
+@@ -1 +1 @@
+-u
++v
+`,
+			want: []file{
+				{oldName: "tab\tname.txt", newName: "tab\tname.txt", added: 1, removed: 1},
+				{oldName: "ümlaut.txt", newName: "ümlaut.txt", added: 1, removed: 1},
+			},
+		},
+		{
+			name: "rename to quoted name",
+			text: `diff --git a/plain.txt "b/new n\303\244me.txt"
+similarity index 100%
+rename from plain.txt
+rename to "new n\303\244me.txt"
+`,
+			want: []file{{oldName: "plain.txt", newName: "new näme.txt"}},
+		},
+		{
+			name: "text before the first file",
+			text: `commit 7777777
+Subject line
+
+diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1 +1 @@
+-old
++new
+`,
+			want: []file{{oldName: "a.txt", newName: "a.txt", added: 1, removed: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []file
+			for _, f := range files {
+				added, removed := f.Stats()
+				got = append(got, file{
+					oldName:  f.OldName,
+					newName:  f.NewName,
+					isNew:    f.IsNew,
+					isDelete: f.IsDelete,
+					isBinary: f.IsBinary,
+					added:    added,
+					removed:  removed,
+				})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLineNumbers(t *testing.T) {
+	files, err := Parse(`diff --git a/f.txt b/f.txt
+--- a/f.txt
++++ b/f.txt
+@@ -2,3 +2,3 @@ func f() {
+ one
+-two
++TWO
+ three
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Line{
+		{Kind: Context, Content: "one", OldLine: 2, NewLine: 2},
+		{Kind: Removed, Content: "two", OldLine: 3},
+		{Kind: Added, Content: "TWO", NewLine: 3},
+		{Kind: Context, Content: "three", OldLine: 4, NewLine: 4},
+	}
+	if got := files[0].Hunks[0].Lines; !reflect.DeepEqual(got, want) {
+		t.Errorf("lines = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseInvalidHunkHeader(t *testing.T) {
+	_, err := Parse("diff --git a/f b/f\n--- a/f\n+++ b/f\n@@ broken @@\n")
+	if err == nil {
+		t.Error("expected an error for an invalid hunk header")
+	}
+}
+
+func TestMapLine(t *testing.T) {
+	// Line 2 is replaced by two lines, lines 6 and 7 are removed and a line
+	// is added at the end
+	files, err := Parse(`diff --git a/f.txt b/f.txt
+--- a/f.txt
++++ b/f.txt
+@@ -1,3 +1,4 @@
+ 1
+-2
++2a
++2b
+ 3
+@@ -5,4 +6,2 @@
+ 5
+-6
+-7
+ 8
+@@ -10,0 +10,1 @@
++11
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := files[0]
+
+	tests := []struct {
+		name    string
+		oldLine int
+		want    int
+	}{
+		{"context before change", 1, 1},
+		{"replaced line", 2, 2},
+		{"context after insertion", 3, 4},
+		{"between hunks", 4, 5},
+		{"context in second hunk", 5, 6},
+		{"first removed line", 6, 7},
+		{"last removed line", 7, 7},
+		{"context after removal", 8, 7},
+		{"between removal and addition", 9, 8},
+		{"last line before addition", 10, 9},
+		{"after all hunks", 20, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := file.MapLine(tt.oldLine); got != tt.want {
+				t.Errorf("MapLine(%d) = %d, want %d", tt.oldLine, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindFile(t *testing.T) {
+	files := []*File{
+		{OldName: "old.go", NewName: "new.go"},
+		{OldName: "same.go", NewName: "same.go"},
+	}
+
+	tests := []struct {
+		path string
+		want *File
+	}{
+		{"new.go", files[0]},
+		{"old.go", files[0]},
+		{"same.go", files[1]},
+		{"missing.go", nil},
+	}
+
+	for _, tt := range tests {
+		if got := FindFile(files, tt.path); got != tt.want {
+			t.Errorf("FindFile(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
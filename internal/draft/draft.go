@@ -0,0 +1,125 @@
+// Package draft keeps the values of pull requests that weren't created yet,
+// so a cancelled dialog or a failed push doesn't lose them.
+package draft
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Draft is a pull request as entered before it was created.
+type Draft struct {
+	// Repository is the worktree root and Branch the branch the draft
+	// belongs to, empty for a detached HEAD. The topic is part of the
+	// draft, it can be changed in the dialog.
+	Repository  string    `json:"repository"`
+	Branch      string    `json:"branch"`
+	Topic       string    `json:"topic"`
+	Saved       time.Time `json:"saved"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Target      string    `json:"target"`
+	Reviewers   []string  `json:"reviewers,omitempty"`
+	Assignees   []string  `json:"assignees,omitempty"`
+	Labels      []string  `json:"labels,omitempty"`
+	Milestone   string    `json:"milestone,omitempty"`
+}
+
+// dir returns the directory drafts are kept in, below $XDG_STATE_HOME or
+// ~/.local/state.
+func dir() (string, error) {
+	state := os.Getenv("XDG_STATE_HOME")
+	if state == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to find state directory: %w", err)
+		}
+		state = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(state, "lasergit", "drafts"), nil
+}
+
+// path returns the file of the draft for branch in the repository at root.
+func path(root, branch string) (string, error) {
+	dir, err := dir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(root + "\x00" + branch))
+	return filepath.Join(dir, hex.EncodeToString(sum[:16])+".json"), nil
+}
+
+// Load returns the draft for branch in the repository at root, or nil if
+// there is none.
+func Load(root, branch string) (*Draft, error) {
+	path, err := path(root, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read draft: %w", err)
+	}
+
+	var d Draft
+	if err := json.Unmarshal(data, &d); err != nil {
+		return nil, fmt.Errorf("failed to parse draft %s: %w", path, err)
+	}
+	return &d, nil
+}
+
+// Save stores d as the draft for its repository and branch, replacing an
+// earlier one, and sets the time it was saved.
+func Save(d *Draft) error {
+	path, err := path(d.Repository, d.Branch)
+	if err != nil {
+		return err
+	}
+
+	d.Saved = time.Now()
+
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode draft: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create draft directory: %w", err)
+	}
+
+	// Write to a temporary file first, so a failed write keeps the old draft
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write draft: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write draft: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the draft for branch in the repository at root, if there
+// is one.
+func Delete(root, branch string) error {
+	path, err := path(root, branch)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete draft: %w", err)
+	}
+	return nil
+}
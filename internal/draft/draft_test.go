@@ -0,0 +1,95 @@
+package draft
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	saved := &Draft{
+		Repository:  "/src/project",
+		Branch:      "feature/login",
+		Topic:       "alice/login",
+		Title:       "Add login",
+		Description: "With a form.\n\n- [ ] Tests",
+		Target:      "main",
+		Reviewers:   []string{"bob"},
+		Labels:      []string{"enhancement"},
+		Milestone:   "v1.0",
+	}
+	if err := Save(saved); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if saved.Saved.IsZero() {
+		t.Error("Save() didn't set the time it was saved")
+	}
+
+	loaded, err := Load("/src/project", "feature/login")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if loaded == nil || !loaded.Saved.Equal(saved.Saved) {
+		t.Fatalf("Load() = %+v, want %+v", loaded, saved)
+	}
+	loaded.Saved = saved.Saved
+	if !reflect.DeepEqual(loaded, saved) {
+		t.Errorf("Load() = %+v, want %+v", loaded, saved)
+	}
+
+	// Drafts belong to their repository and branch
+	for _, key := range [][2]string{{"/src/other", "feature/login"}, {"/src/project", "main"}} {
+		if other, err := Load(key[0], key[1]); err != nil || other != nil {
+			t.Errorf("Load(%q, %q) = %+v, %v, want no draft", key[0], key[1], other, err)
+		}
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	d, err := Load("/src/project", "feature")
+	if err != nil || d != nil {
+		t.Errorf("Load() = %+v, %v, want no draft and no error", d, err)
+	}
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	path, err := path("/src/project", "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Save(&Draft{Repository: "/src/project", Branch: "feature"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(`{"title": "cut off`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if d, err := Load("/src/project", "feature"); err == nil {
+		t.Errorf("Load() = %+v, want an error", d)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	if err := Save(&Draft{Repository: "/src/project", Branch: "feature", Title: "Add login"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Delete("/src/project", "feature"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if d, err := Load("/src/project", "feature"); err != nil || d != nil {
+		t.Errorf("Load() after Delete() = %+v, %v, want no draft", d, err)
+	}
+
+	// Deleting a missing draft is not an error
+	if err := Delete("/src/project", "feature"); err != nil {
+		t.Errorf("Delete() of a missing draft error = %v", err)
+	}
+}
@@ -1,21 +1,31 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 type Repository struct {
-	repo *git.Repository
+	repo   *git.Repository
+	path   string
+	runner Runner
 }
 
 type Commit struct {
 	Hash    string
 	Message string
 	Author  string
+	// IsMerge is set for commits with more than one parent
+	IsMerge bool
 }
 
 func OpenRepository(path string) (*Repository, error) {
@@ -24,7 +34,36 @@ func OpenRepository(path string) (*Repository, error) {
 		return nil, err
 	}
 
-	return &Repository{repo: repo}, nil
+	// Resolve the worktree root so git subprocesses run in the opened
+	// repository rather than the current working directory.
+	root := path
+	if wt, err := repo.Worktree(); err == nil {
+		root = wt.Filesystem.Root()
+	}
+
+	return &Repository{
+		repo:   repo,
+		path:   root,
+		runner: NewExecRunner(root),
+	}, nil
+}
+
+// Path returns the root of the repository's worktree.
+func (r *Repository) Path() string {
+	return r.path
+}
+
+// SetRunner replaces the runner used for git subprocesses.
+func (r *Repository) SetRunner(runner Runner) {
+	r.runner = runner
+}
+
+// DisablePrompts makes git fail instead of prompting on the terminal, which
+// the TUI draws on while git runs in the background.
+func (r *Repository) DisablePrompts() {
+	if runner, ok := r.runner.(*ExecRunner); ok {
+		runner.NoPrompt = true
+	}
 }
 
 func (r *Repository) GetCurrentBranch() (string, error) {
@@ -50,6 +89,76 @@ func (r *Repository) GetRemoteURL(remoteName string) (string, error) {
 	return urls[0], nil
 }
 
+// GetRemoteHead returns the branch that refs/remotes/<remote>/HEAD points to,
+// i.e. the remote's default branch as recorded when cloning.
+func (r *Repository) GetRemoteHead(remoteName string) (string, error) {
+	ref, err := r.repo.Reference(plumbing.NewRemoteHEADReferenceName(remoteName), false)
+	if err != nil {
+		return "", err
+	}
+
+	if ref.Type() != plumbing.SymbolicReference {
+		return "", fmt.Errorf("%s is not a symbolic reference", ref.Name())
+	}
+
+	return strings.TrimPrefix(ref.Target().String(), "refs/remotes/"+remoteName+"/"), nil
+}
+
+// ListRemoteBranches returns the sorted names of the remote-tracking branches
+// of the given remote, without the remote prefix.
+func (r *Repository) ListRemoteBranches(remoteName string) ([]string, error) {
+	refs, err := r.repo.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+
+	prefix := "refs/remotes/" + remoteName + "/"
+	var branches []string
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().String()
+		if ref.Type() == plumbing.HashReference && strings.HasPrefix(name, prefix) {
+			branches = append(branches, strings.TrimPrefix(name, prefix))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(branches)
+	return branches, nil
+}
+
+// HasBranch reports whether the local branch exists.
+func (r *Repository) HasBranch(name string) bool {
+	_, err := r.repo.Reference(plumbing.NewBranchReferenceName(name), false)
+	return err == nil
+}
+
+// ResolveRevision returns the hash of the commit revision refers to.
+func (r *Repository) ResolveRevision(revision string) (string, error) {
+	hash, err := r.repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", revision, err)
+	}
+
+	return hash.String(), nil
+}
+
+// Diff returns the unified diff between two revisions, limited to the given
+// paths if there are any.
+func (r *Repository) Diff(from, to string, paths ...string) (string, error) {
+	args := append([]string{"diff", "--no-color", "--no-ext-diff", from, to, "--"}, paths...)
+
+	output, err := r.runner.Run(args...)
+	if err != nil {
+		return "", fmt.Errorf("git diff failed: %s", string(output))
+	}
+
+	return string(output), nil
+}
+
 func (r *Repository) GetLatestCommit() (*Commit, error) {
 	head, err := r.repo.Head()
 	if err != nil {
@@ -61,33 +170,215 @@ func (r *Repository) GetLatestCommit() (*Commit, error) {
 		return nil, err
 	}
 
+	return newCommit(commit), nil
+}
+
+func newCommit(commit *object.Commit) *Commit {
 	return &Commit{
 		Hash:    commit.Hash.String(),
 		Message: strings.TrimSpace(commit.Message),
 		Author:  commit.Author.Name,
-	}, nil
+		IsMerge: commit.NumParents() > 1,
+	}
 }
 
-func (r *Repository) PushAGit(targetBranch string, pushOptions []string) error {
-	cmd := exec.Command("git", "push", "origin", fmt.Sprintf("HEAD:refs/for/%s", targetBranch))
-	
-	for _, option := range pushOptions {
-		cmd.Args = append(cmd.Args, "-o", option)
+// Subject returns the first line of the commit message.
+func (c *Commit) Subject() string {
+	subject, _, _ := strings.Cut(c.Message, "\n")
+	return strings.TrimSpace(subject)
+}
+
+// Body returns the commit message without the subject.
+func (c *Commit) Body() string {
+	_, body, _ := strings.Cut(c.Message, "\n")
+	return strings.TrimSpace(body)
+}
+
+// FileStat is the number of lines added and removed in a file.
+type FileStat struct {
+	Path    string
+	Added   int
+	Removed int
+}
+
+// commitFormat prints the hash, author, parents and message of a commit,
+// separated by NUL and terminated by a record separator, which can't occur
+// in any of them.
+const commitFormat = "--format=%H%x00%an%x00%P%x00%B%x1e"
+
+// CommitsSince returns the commits reachable from HEAD but not from the
+// revision base, oldest first, like git log --reverse base..HEAD.
+func (r *Repository) CommitsSince(base string) ([]*Commit, error) {
+	output, err := r.runner.Output("log", "--reverse", "--no-color", commitFormat, base+"..HEAD", "--")
+	if err != nil {
+		return nil, fmt.Errorf("git log failed: %w", err)
+	}
+
+	var commits []*Commit
+	for _, record := range strings.Split(string(output), "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, "\x00", 4)
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected git log output %q", record)
+		}
+		commits = append(commits, &Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Message: strings.TrimSpace(fields[3]),
+			IsMerge: len(strings.Fields(fields[2])) > 1,
+		})
+	}
+	return commits, nil
+}
+
+// DiffStat returns the files changed between the merge base of HEAD and the
+// revision base and HEAD, like git diff --stat base...HEAD. Binary files are
+// listed without added and removed lines.
+func (r *Repository) DiffStat(base string) ([]FileStat, error) {
+	output, err := r.runner.Output("diff", "--numstat", "-z", "--no-renames", "--no-color", "--no-ext-diff", base+"...HEAD", "--")
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %w", err)
+	}
+
+	// Each file is "added\tremoved\tpath", with "-" counts for binary files
+	var stats []FileStat
+	for _, line := range strings.Split(string(output), "\x00") {
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected git diff output %q", line)
+		}
+		added, _ := strconv.Atoi(fields[0])
+		removed, _ := strconv.Atoi(fields[1])
+		stats = append(stats, FileStat{Path: fields[2], Added: added, Removed: removed})
 	}
+	return stats, nil
+}
 
-	output, err := cmd.CombinedOutput()
+// HasUncommittedChanges reports whether tracked files were changed or
+// staged without being committed. Untracked files are ignored.
+func (r *Repository) HasUncommittedChanges() (bool, error) {
+	wt, err := r.repo.Worktree()
 	if err != nil {
-		return fmt.Errorf("git push failed: %s", string(output))
+		return false, fmt.Errorf("failed to get worktree: %w", err)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return false, fmt.Errorf("failed to get worktree status: %w", err)
+	}
+
+	changed := func(code git.StatusCode) bool {
+		return code != git.Unmodified && code != git.Untracked
+	}
+	for _, file := range status {
+		if changed(file.Staging) || changed(file.Worktree) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ConfigValue returns the value of the git config key, or "" if it isn't
+// set.
+func (r *Repository) ConfigValue(key string) (string, error) {
+	output, err := r.runner.Run("config", "--get", key)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("git config failed: %s", string(output))
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SetConfigValue sets the git config key in the config of the repository.
+func (r *Repository) SetConfigValue(key, value string) error {
+	output, err := r.runner.Run("config", key, value)
+	if err != nil {
+		return fmt.Errorf("git config failed: %s", string(output))
 	}
 
 	return nil
 }
 
+// BranchTopic returns the AGit topic remembered for branch, or "" if there
+// is none.
+func (r *Repository) BranchTopic(branch string) (string, error) {
+	return r.ConfigValue(branchTopicKey(branch))
+}
+
+// SetBranchTopic remembers topic as the AGit topic of branch.
+func (r *Repository) SetBranchTopic(branch, topic string) error {
+	return r.SetConfigValue(branchTopicKey(branch), topic)
+}
+
+func branchTopicKey(branch string) string {
+	return fmt.Sprintf("branch.%s.lasergitTopic", branch)
+}
+
+// Editor returns the editor git uses for commit messages, chosen from
+// $GIT_EDITOR, core.editor, $VISUAL and $EDITOR.
+func (r *Repository) Editor() (string, error) {
+	// Warnings on stderr must not end up in the command
+	output, err := r.runner.Output("var", "GIT_EDITOR")
+	if err != nil {
+		return "", fmt.Errorf("git var failed: %w", err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// PushAGit pushes HEAD to refs/for/<targetBranch> with the given push
+// options and returns the output of git push, which includes the messages
+// of the server.
+func (r *Repository) PushAGit(targetBranch string, pushOptions []string) (string, error) {
+	args := []string{"push", "origin", fmt.Sprintf("HEAD:refs/for/%s", targetBranch)}
+
+	for _, option := range pushOptions {
+		args = append(args, "-o", option)
+	}
+
+	output, err := r.runner.Run(args...)
+	if err != nil {
+		return "", fmt.Errorf("git push failed: %s", string(output))
+	}
+
+	return string(output), nil
+}
+
+// FetchPullRequest fetches the head of pull request prNumber into the local
+// branch branchName, replacing it when the pull request was force-pushed.
+// git fetch refuses to update the checked out branch, so that one is moved
+// with git reset --keep instead, which fails rather than overwrite
+// uncommitted changes.
 func (r *Repository) FetchPullRequest(remoteName string, prNumber int, branchName string) error {
-	refSpec := fmt.Sprintf("pull/%d/head:%s", prNumber, branchName)
-	
-	cmd := exec.Command("git", "fetch", remoteName, refSpec)
-	output, err := cmd.CombinedOutput()
+	if current, _ := r.GetCurrentBranch(); current == branchName {
+		output, err := r.runner.Run("fetch", remoteName, fmt.Sprintf("pull/%d/head", prNumber))
+		if err != nil {
+			return fmt.Errorf("git fetch failed: %s", string(output))
+		}
+
+		output, err = r.runner.Run("reset", "--keep", "FETCH_HEAD")
+		if err != nil {
+			return fmt.Errorf("failed to update checked out branch %s: %s", branchName, string(output))
+		}
+		return nil
+	}
+
+	refSpec := fmt.Sprintf("+pull/%d/head:%s", prNumber, branchName)
+
+	output, err := r.runner.Run("fetch", remoteName, refSpec)
 	if err != nil {
 		return fmt.Errorf("git fetch failed: %s", string(output))
 	}
@@ -96,11 +387,34 @@ func (r *Repository) FetchPullRequest(remoteName string, prNumber int, branchNam
 }
 
 func (r *Repository) CheckoutBranch(branchName string) error {
-	cmd := exec.Command("git", "checkout", branchName)
-	output, err := cmd.CombinedOutput()
+	output, err := r.runner.Run("checkout", branchName)
 	if err != nil {
 		return fmt.Errorf("git checkout failed: %s", string(output))
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+var prBranchRegex = regexp.MustCompile(`^agit-(\d+)$`)
+
+// PRBranchName returns the local branch pull request number is checked out
+// as.
+func PRBranchName(number int64) string {
+	return fmt.Sprintf("agit-%d", number)
+}
+
+// PRNumberFromBranch returns the number of the pull request checked out as
+// branch, and false if branch isn't one of the branches named by
+// PRBranchName.
+func PRNumberFromBranch(branch string) (int64, bool) {
+	matches := prBranchRegex.FindStringSubmatch(branch)
+	if len(matches) != 2 {
+		return 0, false
+	}
+
+	number, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,290 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// recordingRunner records the arguments of every git invocation instead of
+// running git, and answers each with output, preceded by stderr for Run.
+type recordingRunner struct {
+	calls  [][]string
+	output string
+	stderr string
+}
+
+func (r *recordingRunner) Run(args ...string) ([]byte, error) {
+	r.calls = append(r.calls, args)
+	return []byte(r.stderr + r.output), nil
+}
+
+func (r *recordingRunner) Output(args ...string) ([]byte, error) {
+	r.calls = append(r.calls, args)
+	return []byte(r.output), nil
+}
+
+// testRepository returns a repository with a single commit on branch, whose
+// git subprocesses are recorded by the returned runner.
+func testRepository(t *testing.T, branch string) (*Repository, *recordingRunner) {
+	t.Helper()
+
+	repo, err := git.PlainInit(t.TempDir(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("initial", &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refName := plumbing.NewBranchReferenceName(branch)
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(refName, hash)); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, refName)); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &recordingRunner{}
+	r := &Repository{repo: repo, path: wt.Filesystem.Root()}
+	r.SetRunner(runner)
+	return r, runner
+}
+
+func TestRepositoryArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		// branch is checked out in the test repository
+		branch string
+		run    func(r *Repository) error
+		want   [][]string
+	}{
+		{
+			name:   "push agit",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.PushAGit("main", []string{"topic=fix", "title=Fix it"})
+				return err
+			},
+			want: [][]string{{"push", "origin", "HEAD:refs/for/main", "-o", "topic=fix", "-o", "title=Fix it"}},
+		},
+		{
+			name:   "push agit without options",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.PushAGit("release/1.0", nil)
+				return err
+			},
+			want: [][]string{{"push", "origin", "HEAD:refs/for/release/1.0"}},
+		},
+		{
+			name:   "fetch pull request",
+			branch: "main",
+			run: func(r *Repository) error {
+				return r.FetchPullRequest("origin", 7, "agit-7")
+			},
+			want: [][]string{{"fetch", "origin", "+pull/7/head:agit-7"}},
+		},
+		{
+			name:   "fetch checked out pull request",
+			branch: "agit-7",
+			run: func(r *Repository) error {
+				return r.FetchPullRequest("origin", 7, "agit-7")
+			},
+			want: [][]string{
+				{"fetch", "origin", "pull/7/head"},
+				{"reset", "--keep", "FETCH_HEAD"},
+			},
+		},
+		{
+			name:   "checkout branch",
+			branch: "main",
+			run: func(r *Repository) error {
+				return r.CheckoutBranch("agit-7")
+			},
+			want: [][]string{{"checkout", "agit-7"}},
+		},
+		{
+			name:   "diff",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.Diff("abc123", "agit-7")
+				return err
+			},
+			want: [][]string{{"diff", "--no-color", "--no-ext-diff", "abc123", "agit-7", "--"}},
+		},
+		{
+			name:   "diff with paths",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.Diff("abc123", "def456", "main.go", "-odd name")
+				return err
+			},
+			want: [][]string{{"diff", "--no-color", "--no-ext-diff", "abc123", "def456", "--", "main.go", "-odd name"}},
+		},
+		{
+			name:   "commits since",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.CommitsSince("origin/main")
+				return err
+			},
+			want: [][]string{{"log", "--reverse", "--no-color", commitFormat, "origin/main..HEAD", "--"}},
+		},
+		{
+			name:   "diff stat",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.DiffStat("origin/main")
+				return err
+			},
+			want: [][]string{{"diff", "--numstat", "-z", "--no-renames", "--no-color", "--no-ext-diff", "origin/main...HEAD", "--"}},
+		},
+		{
+			name:   "editor",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.Editor()
+				return err
+			},
+			want: [][]string{{"var", "GIT_EDITOR"}},
+		},
+		{
+			name:   "get config",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.ConfigValue("lasergit.topicPrefix")
+				return err
+			},
+			want: [][]string{{"config", "--get", "lasergit.topicPrefix"}},
+		},
+		{
+			name:   "set config",
+			branch: "main",
+			run: func(r *Repository) error {
+				return r.SetConfigValue("lasergit.topicPrefix", "alice/")
+			},
+			want: [][]string{{"config", "lasergit.topicPrefix", "alice/"}},
+		},
+		{
+			name:   "branch topic",
+			branch: "main",
+			run: func(r *Repository) error {
+				_, err := r.BranchTopic("feature")
+				return err
+			},
+			want: [][]string{{"config", "--get", "branch.feature.lasergitTopic"}},
+		},
+		{
+			name:   "set branch topic",
+			branch: "main",
+			run: func(r *Repository) error {
+				return r.SetBranchTopic("feature", "alice/feature")
+			},
+			want: [][]string{{"config", "branch.feature.lasergitTopic", "alice/feature"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, runner := testRepository(t, tt.branch)
+			if err := tt.run(r); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(runner.calls, tt.want) {
+				t.Errorf("git was run with\n%q\nwant\n%q", runner.calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitsSince(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []*Commit
+	}{
+		{
+			name: "no commits",
+		},
+		{
+			name:   "commits",
+			output: "aaa\x00Alice\x00base\x00Add login\n\nWith a form.\n\x1e\nbbb\x00Bob\x00aaa\x00Fix typo\n\x1e\n",
+			want: []*Commit{
+				{Hash: "aaa", Author: "Alice", Message: "Add login\n\nWith a form."},
+				{Hash: "bbb", Author: "Bob", Message: "Fix typo"},
+			},
+		},
+		{
+			name:   "merge",
+			output: "ccc\x00Alice\x00aaa bbb\x00Merge branch 'main'\n\x1e\n",
+			want:   []*Commit{{Hash: "ccc", Author: "Alice", Message: "Merge branch 'main'", IsMerge: true}},
+		},
+		{
+			name:   "root commit",
+			output: "ddd\x00Alice\x00\x00Initial commit\n\x1e\n",
+			want:   []*Commit{{Hash: "ddd", Author: "Alice", Message: "Initial commit"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, runner := testRepository(t, "main")
+			runner.output = tt.output
+
+			got, err := r.CommitsSince("origin/main")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CommitsSince() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditor(t *testing.T) {
+	r, runner := testRepository(t, "main")
+	runner.output = "vim -f\n"
+	runner.stderr = "warning: unable to access '/home/alice/.config/git/attributes'\n"
+
+	got, err := r.Editor()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "vim -f" {
+		t.Errorf("Editor() = %q, want %q", got, "vim -f")
+	}
+}
+
+func TestDiffStat(t *testing.T) {
+	r, runner := testRepository(t, "main")
+	runner.output = "3\t1\tmain.go\x00" + "0\t2\tdir/with\ttab.txt\x00" + "-\t-\tlogo.png\x00"
+
+	got, err := r.DiffStat("origin/main")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []FileStat{
+		{Path: "main.go", Added: 3, Removed: 1},
+		{Path: "dir/with\ttab.txt", Removed: 2},
+		{Path: "logo.png"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffStat() = %+v, want %+v", got, want)
+	}
+}
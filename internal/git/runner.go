@@ -0,0 +1,81 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Runner executes git with the given arguments. Repository routes every git
+// subprocess through a Runner so that tests can substitute one that records
+// the argv instead.
+type Runner interface {
+	// Run returns the combined stdout and stderr of git.
+	Run(args ...string) ([]byte, error)
+	// Output returns the stdout of git, for output that is parsed. stderr
+	// is part of the error if git fails.
+	Output(args ...string) ([]byte, error)
+}
+
+// ExecRunner runs the git binary inside Dir.
+type ExecRunner struct {
+	Dir string
+	// NoPrompt makes git fail instead of asking for credentials, SSH
+	// passphrases or host keys, for when the terminal belongs to the TUI.
+	NoPrompt bool
+}
+
+func NewExecRunner(dir string) *ExecRunner {
+	return &ExecRunner{Dir: dir}
+}
+
+// promptFailures are printed by git and ssh when they would have needed to
+// ask the user for something.
+var promptFailures = []string{
+	"terminal prompts disabled",
+	"Permission denied",
+	"Host key verification failed",
+}
+
+func (r *ExecRunner) command(args []string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	if !r.NoPrompt {
+		return cmd
+	}
+
+	sshCommand := os.Getenv("GIT_SSH_COMMAND")
+	if sshCommand == "" {
+		sshCommand = "ssh"
+	}
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_SSH_COMMAND="+sshCommand+" -o BatchMode=yes",
+	)
+	return cmd
+}
+
+func (r *ExecRunner) Run(args ...string) ([]byte, error) {
+	output, err := r.command(args).CombinedOutput()
+	if err != nil && r.NoPrompt {
+		for _, failure := range promptFailures {
+			if strings.Contains(string(output), failure) {
+				output = append(output, "\nlasergit can't ask for credentials here, use a credential helper or ssh-agent, or run the command outside the TUI"...)
+				break
+			}
+		}
+	}
+	return output, err
+}
+
+func (r *ExecRunner) Output(args ...string) ([]byte, error) {
+	output, err := r.command(args).Output()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return output, fmt.Errorf("%w: %s", err, strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return output, err
+}
@@ -0,0 +1,83 @@
+package gitea
+
+import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// base64Prefix marks push option values Gitea decodes as base64.
+const base64Prefix = "{base64}"
+
+// maxPushOptionLength is the longest push option git can send. Each option
+// is sent as a single pkt-line, whose payload is limited to 65516 bytes.
+const maxPushOptionLength = 65516
+
+// pullURLRegex matches the pull request links Gitea prints after AGit
+// pushes, like "https://gitea.example.com/owner/repo/pulls/42".
+var pullURLRegex = regexp.MustCompile(`https?://\S+/pulls/(\d+)`)
+
+// PushResult is what the server reported about the pull request of an AGit
+// push.
+type PushResult struct {
+	// Index is the number of the pull request, 0 if none was reported
+	Index int64
+	URL   string
+}
+
+// ParsePushOutput reads the pull request link from the "remote:" lines of
+// the output of an AGit push.
+func ParsePushOutput(output string) PushResult {
+	for _, line := range strings.Split(output, "\n") {
+		message, ok := strings.CutPrefix(strings.TrimSpace(line), "remote:")
+		if !ok {
+			continue
+		}
+
+		matches := pullURLRegex.FindStringSubmatch(message)
+		if len(matches) != 2 {
+			continue
+		}
+
+		index, err := strconv.ParseInt(matches[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		return PushResult{Index: index, URL: matches[0]}
+	}
+
+	return PushResult{}
+}
+
+// PushOption formats the AGit push option key=value. Values a push option
+// can't carry as is, like multi-line descriptions, are base64 encoded with
+// the prefix Gitea decodes for the title and description.
+func PushOption(key, value string) string {
+	if needsBase64(value) {
+		value = base64Prefix + base64.StdEncoding.EncodeToString([]byte(value))
+	}
+	return key + "=" + value
+}
+
+// CheckPushOption returns an error if the push option PushOption formats
+// for key and value is too long for git to send. The limit is in bytes of
+// the encoded option, so it depends on the characters used.
+func CheckPushOption(key, value string) error {
+	if length := len(PushOption(key, value)); length > maxPushOptionLength {
+		return fmt.Errorf("%s is too long, %d of at most %d bytes once encoded as push option", key, length, maxPushOptionLength)
+	}
+	return nil
+}
+
+// needsBase64 reports whether value would be mangled as a plain push
+// option: push options end at newlines, and surrounding whitespace and
+// control characters don't survive the trip.
+func needsBase64(value string) bool {
+	if strings.HasPrefix(value, base64Prefix) || strings.TrimSpace(value) != value {
+		return true
+	}
+	return strings.IndexFunc(value, unicode.IsControl) >= 0
+}
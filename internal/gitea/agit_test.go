@@ -0,0 +1,119 @@
+package gitea
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestParsePushOutput(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   PushResult
+	}{
+		{
+			name: "created pull request",
+			output: `Enumerating objects: 5, done.
+Writing objects: 100% (3/3), 300 bytes | 300.00 KiB/s, done.
+remote:
+remote: Visit the existing pull request:
+remote:   https://gitea.example.com/owner/repo/pulls/42
+remote:
+To gitea.example.com:owner/repo.git
+ * [new reference]   HEAD -> refs/for/main`,
+			want: PushResult{Index: 42, URL: "https://gitea.example.com/owner/repo/pulls/42"},
+		},
+		{
+			name:   "http and subpath",
+			output: "remote:   http://localhost:3000/git/owner/repo/pulls/7\n",
+			want:   PushResult{Index: 7, URL: "http://localhost:3000/git/owner/repo/pulls/7"},
+		},
+		{
+			name:   "first link wins",
+			output: "remote: https://gitea.example.com/o/r/pulls/1\nremote: https://gitea.example.com/o/r/pulls/2\n",
+			want:   PushResult{Index: 1, URL: "https://gitea.example.com/o/r/pulls/1"},
+		},
+		{
+			name:   "link outside remote messages",
+			output: "see https://gitea.example.com/o/r/pulls/3\n",
+			want:   PushResult{},
+		},
+		{
+			name:   "no pull request link",
+			output: "remote: Create a new pull request for 'feature':\nremote:   https://gitea.example.com/o/r/compare/main...feature\n",
+			want:   PushResult{},
+		},
+		{
+			name:   "empty output",
+			output: "",
+			want:   PushResult{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParsePushOutput(tt.output); got != tt.want {
+				t.Errorf("ParsePushOutput() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPushOption(t *testing.T) {
+	encoded := func(value string) string {
+		return base64Prefix + base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"plain", "Fix typo", "title=Fix typo"},
+		{"empty", "", "title="},
+		{"unicode", "Füge 🚀 hinzu", "title=Füge 🚀 hinzu"},
+		{"equals sign", "a=b", "title=a=b"},
+		{"multiple lines", "first\nsecond", "title=" + encoded("first\nsecond")},
+		{"carriage return", "a\r\nb", "title=" + encoded("a\r\nb")},
+		{"tab", "a\tb", "title=" + encoded("a\tb")},
+		{"leading space", " indented", "title=" + encoded(" indented")},
+		{"trailing newline", "text\n", "title=" + encoded("text\n")},
+		{"base64 prefix", "{base64}not encoded", "title=" + encoded("{base64}not encoded")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PushOption("title", tt.value); got != tt.want {
+				t.Errorf("PushOption(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckPushOption(t *testing.T) {
+	// "description=" takes 12 bytes of the limit
+	fitting := strings.Repeat("a", maxPushOptionLength-len("description="))
+
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{"empty", "", false},
+		{"longest plain value", fitting, false},
+		{"one byte too long", fitting + "a", true},
+		// Emoji take 4 bytes each, base64 encoding adds a third
+		{"multi-byte characters", strings.Repeat("🚀", 13000) + "\n", true},
+		{"few multi-byte characters", strings.Repeat("🚀", 1000) + "\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckPushOption("description", tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPushOption() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
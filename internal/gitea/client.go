@@ -6,14 +6,29 @@ import (
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 
 	"code.gitea.io/sdk/gitea"
 )
 
 type Client struct {
-	client *gitea.Client
+	client  *gitea.Client
+	baseURL string
+	token   string
+
+	// pageSize is read from the server once, pagers may run concurrently
+	pageSizeOnce sync.Once
+	pageSize     int
+
+	// userName is the authenticated user, also read once
+	userOnce sync.Once
+	userName string
+	userErr  error
 }
 
+// defaultPageSize is used when the server's API settings can't be read.
+const defaultPageSize = 50
+
 func NewClient(baseURL string) (*Client, error) {
 	token := os.Getenv("GITEA_TOKEN")
 
@@ -22,18 +37,167 @@ func NewClient(baseURL string) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{client: client}, nil
+	return &Client{client: client, baseURL: strings.TrimSuffix(baseURL, "/"), token: token}, nil
+}
+
+func (c *Client) GetDefaultBranch(owner, repo string) (string, error) {
+	r, _, err := c.client.GetRepo(owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	if r.DefaultBranch == "" {
+		return "", fmt.Errorf("repository %s/%s has no default branch", owner, repo)
+	}
+
+	return r.DefaultBranch, nil
+}
+
+// ListPullRequests returns all pull requests matching filter, fetching every
+// page.
+func (c *Client) ListPullRequests(owner, repo string, filter PRFilter) ([]*gitea.PullRequest, error) {
+	pager := c.NewPullRequestPager(owner, repo, filter)
+
+	var prs []*gitea.PullRequest
+	for pager.HasMore() {
+		page, err := pager.Next()
+		if err != nil {
+			return nil, err
+		}
+		prs = append(prs, page...)
+	}
+
+	return prs, nil
+}
+
+// PullRequestPager fetches pull requests one page at a time, so callers can
+// load further pages lazily. Pages are filtered, so a page may be empty even
+// though more pull requests follow.
+type PullRequestPager struct {
+	client *Client
+	owner  string
+	repo   string
+	filter PRFilter
+	page   int
+	done   bool
+}
+
+func (c *Client) NewPullRequestPager(owner, repo string, filter PRFilter) *PullRequestPager {
+	return &PullRequestPager{
+		client: c,
+		owner:  owner,
+		repo:   repo,
+		filter: filter,
+		page:   1,
+	}
+}
+
+// HasMore reports whether another call to Next may return pull requests.
+func (p *PullRequestPager) HasMore() bool {
+	return !p.done
 }
 
-func (c *Client) ListPullRequests(owner, repo string) ([]*gitea.PullRequest, error) {
-	prs, _, err := c.client.ListRepoPullRequests(owner, repo, gitea.ListPullRequestsOptions{
-		State: gitea.StateOpen,
+// Next fetches the next page of pull requests.
+func (p *PullRequestPager) Next() ([]*gitea.PullRequest, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	pageSize := p.client.maxPageSize()
+	prs, resp, err := p.client.client.ListRepoPullRequests(p.owner, p.repo, gitea.ListPullRequestsOptions{
+		ListOptions: gitea.ListOptions{Page: p.page, PageSize: pageSize},
+		State:       p.filter.apiState(),
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return prs, nil
+	p.page++
+	// A short page is always the last one. Otherwise trust the Link header,
+	// which is only present when there is more than one page.
+	hasLinks := resp != nil && (resp.LastPage != 0 || resp.PrevPage != 0)
+	if len(prs) < pageSize || (hasLinks && resp.NextPage == 0) {
+		p.done = true
+	}
+
+	return p.client.filterPullRequests(p.owner, p.repo, prs, p.filter)
+}
+
+// maxPageSize returns the largest page size the server accepts.
+func (c *Client) maxPageSize() int {
+	c.pageSizeOnce.Do(func() {
+		c.pageSize = defaultPageSize
+		settings, _, err := c.client.GetGlobalAPISettings()
+		if err == nil && settings.MaxResponseItems > 0 {
+			c.pageSize = settings.MaxResponseItems
+		}
+	})
+
+	return c.pageSize
+}
+
+func (c *Client) GetPullRequest(owner, repo string, index int64) (*gitea.PullRequest, error) {
+	pr, _, err := c.client.GetPullRequest(owner, repo, index)
+	if err != nil {
+		return nil, err
+	}
+
+	return pr, nil
+}
+
+// CurrentUser returns the name of the user the token belongs to.
+func (c *Client) CurrentUser() (string, error) {
+	c.userOnce.Do(func() {
+		user, _, err := c.client.GetMyUserInfo()
+		if err != nil {
+			c.userErr = fmt.Errorf("failed to get the current user: %w", err)
+			return
+		}
+		c.userName = user.UserName
+	})
+
+	return c.userName, c.userErr
+}
+
+// IsOwnPullRequest reports whether pr was opened by the current user. AGit
+// pushes only update the pull requests of the user pushing.
+func (c *Client) IsOwnPullRequest(pr *gitea.PullRequest) (bool, error) {
+	me, err := c.CurrentUser()
+	if err != nil {
+		return false, err
+	}
+
+	return pr.Poster != nil && strings.EqualFold(pr.Poster.UserName, me), nil
+}
+
+// FindPullRequestByTopic returns the open pull request of the current user
+// whose head is the given AGit topic, or nil if there is none. Pull requests
+// of other users with the same topic are ignored, pushing to the topic
+// creates or updates one of our own.
+func (c *Client) FindPullRequestByTopic(owner, repo, topic string) (*gitea.PullRequest, error) {
+	me, err := c.CurrentUser()
+	if err != nil {
+		return nil, err
+	}
+
+	prs, err := c.ListPullRequests(owner, repo, PRFilter{State: PRStateOpen})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pr := range prs {
+		if pr.Head == nil || pr.Poster == nil || !strings.EqualFold(pr.Poster.UserName, me) {
+			continue
+		}
+		// AGit pull requests report the bare topic as head ref, older
+		// servers prefix it with the pusher's user name.
+		user, name, found := strings.Cut(pr.Head.Ref, "/")
+		if pr.Head.Ref == topic || (found && strings.EqualFold(user, me) && name == topic) {
+			return pr, nil
+		}
+	}
+
+	return nil, nil
 }
 
 func ParseRemoteURL(remoteURL string) (owner, repo, baseURL string, err error) {
@@ -0,0 +1,81 @@
+package gitea
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// newTestClient returns a client for a fake server answering the API paths
+// in responses with their JSON encoding.
+func newTestClient(t *testing.T, responses map[string]any) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestFindPullRequestByTopic(t *testing.T) {
+	pr := func(index int64, poster, head string) map[string]any {
+		return map[string]any{
+			"number": index,
+			"state":  "open",
+			"user":   map[string]any{"login": poster},
+			"head":   map[string]any{"ref": head},
+		}
+	}
+
+	client := newTestClient(t, map[string]any{
+		"/api/v1/version":      map[string]any{"version": "1.22.0"},
+		"/api/v1/settings/api": map[string]any{"max_response_items": 50},
+		"/api/v1/user":         map[string]any{"login": "alice"},
+		"/api/v1/repos/owner/repo/pulls": []any{
+			pr(1, "bob", "fix"),
+			pr(2, "bob", "bob/feature"),
+			pr(3, "alice", "alice/feature"),
+			pr(4, "Alice", "fix"),
+		},
+	})
+
+	tests := []struct {
+		topic string
+		want  int64
+	}{
+		{"fix", 4},
+		{"feature", 3},
+		{"alice/feature", 3},
+		{"bob/feature", 0},
+		{"missing", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			got, err := client.FindPullRequestByTopic("owner", "repo", tt.topic)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var index int64
+			if got != nil {
+				index = got.Index
+			}
+			if index != tt.want {
+				t.Errorf("FindPullRequestByTopic(%q) = #%d, want #%d", tt.topic, index, tt.want)
+			}
+		})
+	}
+}
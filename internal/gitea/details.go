@@ -0,0 +1,127 @@
+package gitea
+
+import (
+	"code.gitea.io/sdk/gitea"
+)
+
+// ListPullRequestCommits returns all commits of a pull request, oldest first.
+func (c *Client) ListPullRequestCommits(owner, repo string, index int64) ([]*gitea.Commit, error) {
+	pageSize := c.maxPageSize()
+
+	var commits []*gitea.Commit
+	for page := 1; ; page++ {
+		batch, _, err := c.client.ListPullRequestCommits(owner, repo, index, gitea.ListPullRequestCommitsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		commits = append(commits, batch...)
+		if len(batch) < pageSize {
+			return commits, nil
+		}
+	}
+}
+
+// ListPullReviews returns all reviews of a pull request, including the
+// pending review requests.
+func (c *Client) ListPullReviews(owner, repo string, index int64) ([]*gitea.PullReview, error) {
+	pageSize := c.maxPageSize()
+
+	var reviews []*gitea.PullReview
+	for page := 1; ; page++ {
+		batch, _, err := c.client.ListPullReviews(owner, repo, index, gitea.ListPullReviewsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		reviews = append(reviews, batch...)
+		if len(batch) < pageSize {
+			return reviews, nil
+		}
+	}
+}
+
+// RequestedReviewers returns the names of the users and teams whose review
+// is still requested, given all reviews of a pull request.
+func RequestedReviewers(reviews []*gitea.PullReview) []string {
+	var reviewers []string
+	for _, review := range reviews {
+		if review.State != gitea.ReviewStateRequestReview {
+			continue
+		}
+
+		switch {
+		case review.Reviewer != nil:
+			reviewers = append(reviewers, review.Reviewer.UserName)
+		case review.ReviewerTeam != nil:
+			reviewers = append(reviewers, review.ReviewerTeam.Name)
+		}
+	}
+
+	return reviewers
+}
+
+// ListReviewComments returns the code comments of the given reviews of a
+// pull request. Reviews without code comments are skipped.
+func (c *Client) ListReviewComments(owner, repo string, index int64, reviews []*gitea.PullReview) ([]*gitea.PullReviewComment, error) {
+	var comments []*gitea.PullReviewComment
+	for _, review := range reviews {
+		if review.CodeCommentsCount == 0 {
+			continue
+		}
+
+		batch, _, err := c.client.ListPullReviewComments(owner, repo, index, review.ID)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, batch...)
+	}
+
+	return comments, nil
+}
+
+// ListIssueComments returns the conversation comments of a pull request,
+// oldest first.
+func (c *Client) ListIssueComments(owner, repo string, index int64) ([]*gitea.Comment, error) {
+	pageSize := c.maxPageSize()
+
+	var comments []*gitea.Comment
+	for page := 1; ; page++ {
+		batch, _, err := c.client.ListIssueComments(owner, repo, index, gitea.ListIssueCommentOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		comments = append(comments, batch...)
+		if len(batch) < pageSize {
+			return comments, nil
+		}
+	}
+}
+
+// CreateIssueComment adds a comment to the conversation of a pull request.
+func (c *Client) CreateIssueComment(owner, repo string, index int64, body string) (*gitea.Comment, error) {
+	comment, _, err := c.client.CreateIssueComment(owner, repo, index, gitea.CreateIssueCommentOption{Body: body})
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetPullRequestDiff returns the unified diff of a pull request against its
+// merge base.
+func (c *Client) GetPullRequestDiff(owner, repo string, index int64) (string, error) {
+	diff, _, err := c.client.GetPullRequestDiff(owner, repo, index, gitea.PullRequestDiffOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return string(diff), nil
+}
@@ -0,0 +1,192 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// PullRequestEdit lists the changes to make to a pull request. Fields left
+// nil are kept as they are.
+type PullRequestEdit struct {
+	Title *string
+	Body  *string
+	// Base is the target branch
+	Base  *string
+	State *gitea.StateType
+	// Assignees are user names, an empty list removes all assignees
+	Assignees *[]string
+	// Labels are label names, an empty list removes all labels
+	Labels *[]string
+	// Milestone is a milestone name, "" removes the milestone
+	Milestone *string
+	// Deadline is the due date, the zero time removes it
+	Deadline *time.Time
+}
+
+// EditPullRequest applies edit to a pull request and returns the result.
+//
+// The SDK's EditPullRequest always sends the body, so it would clear the
+// description whenever it isn't changed. The request is made directly with
+// just the fields to change instead.
+func (c *Client) EditPullRequest(owner, repo string, index int64, edit PullRequestEdit) (*gitea.PullRequest, error) {
+	patch := make(map[string]any)
+	if edit.Title != nil {
+		patch["title"] = *edit.Title
+	}
+	if edit.Body != nil {
+		patch["body"] = *edit.Body
+	}
+	if edit.Base != nil {
+		patch["base"] = *edit.Base
+	}
+	if edit.State != nil {
+		patch["state"] = *edit.State
+	}
+	if edit.Assignees != nil {
+		patch["assignees"] = nonNil(*edit.Assignees)
+	}
+	if edit.Labels != nil {
+		ids, err := c.labelIDs(owner, repo, *edit.Labels)
+		if err != nil {
+			return nil, err
+		}
+		patch["labels"] = ids
+	}
+	if edit.Deadline != nil {
+		if edit.Deadline.IsZero() {
+			patch["unset_due_date"] = true
+		} else {
+			patch["due_date"] = *edit.Deadline
+		}
+	}
+
+	// Editing a pull request can't remove its milestone, that is done on the
+	// issue below
+	removeMilestone := false
+	if edit.Milestone != nil {
+		if *edit.Milestone == "" {
+			removeMilestone = true
+		} else {
+			milestone, _, err := c.client.GetMilestoneByName(owner, repo, *edit.Milestone)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find milestone '%s': %w", *edit.Milestone, err)
+			}
+			patch["milestone"] = milestone.ID
+		}
+	}
+
+	if removeMilestone {
+		path := fmt.Sprintf("/repos/%s/%s/issues/%d", url.PathEscape(owner), url.PathEscape(repo), index)
+		if _, err := c.apiRequest(http.MethodPatch, path, map[string]any{"milestone": 0}, nil); err != nil {
+			return nil, fmt.Errorf("failed to remove milestone: %w", err)
+		}
+	}
+
+	if len(patch) == 0 {
+		return c.GetPullRequest(owner, repo, index)
+	}
+
+	var pr gitea.PullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", url.PathEscape(owner), url.PathEscape(repo), index)
+	if _, err := c.apiRequest(http.MethodPatch, path, patch, &pr); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+// labelIDs resolves label names to the IDs of the repository's labels,
+// including those of its organization.
+func (c *Client) labelIDs(owner, repo string, names []string) ([]int64, error) {
+	ids := make([]int64, 0, len(names))
+	if len(names) == 0 {
+		return ids, nil
+	}
+
+	labels, err := c.ListLabels(owner, repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	byName := make(map[string]int64, len(labels))
+	for _, label := range labels {
+		byName[strings.ToLower(label.Name)] = label.ID
+	}
+
+	var unknown []string
+	for _, name := range names {
+		id, ok := byName[strings.ToLower(name)]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown labels: %s", strings.Join(unknown, ", "))
+	}
+
+	return ids, nil
+}
+
+// ListLabels returns the labels that can be added to pull requests of the
+// repository, those of its organization included.
+func (c *Client) ListLabels(owner, repo string) ([]*gitea.Label, error) {
+	pageSize := c.maxPageSize()
+
+	var labels []*gitea.Label
+	for page := 1; ; page++ {
+		batch, _, err := c.client.ListRepoLabels(owner, repo, gitea.ListLabelsOptions{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, batch...)
+		if len(batch) < pageSize {
+			break
+		}
+	}
+
+	// The SDK can't list organization labels. Repositories of users have
+	// none, the request fails for them.
+	for page := 1; ; page++ {
+		var batch []*gitea.Label
+		path := fmt.Sprintf("/orgs/%s/labels?page=%d&limit=%d", url.PathEscape(owner), page, pageSize)
+		if _, err := c.apiRequest(http.MethodGet, path, nil, &batch); err != nil {
+			break
+		}
+
+		labels = append(labels, batch...)
+		if len(batch) < pageSize {
+			break
+		}
+	}
+
+	return labels, nil
+}
+
+// SplitList splits a comma separated list of names, dropping empty entries.
+func SplitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// nonNil returns an empty slice for nil, which is sent as [] instead of null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+package gitea
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// PRState selects pull requests by their state.
+type PRState string
+
+const (
+	PRStateOpen   PRState = "open"
+	PRStateClosed PRState = "closed" // closed without being merged
+	PRStateMerged PRState = "merged"
+	PRStateAll    PRState = "all"
+)
+
+// PRStates lists all states in the order they are presented to the user.
+var PRStates = []PRState{PRStateOpen, PRStateClosed, PRStateMerged, PRStateAll}
+
+func ParsePRState(s string) (PRState, error) {
+	for _, state := range PRStates {
+		if strings.EqualFold(s, string(state)) {
+			return state, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid state %q, must be one of open, closed, merged, all", s)
+}
+
+// PRFilter narrows down listed pull requests. Empty fields match everything,
+// an empty State means open.
+type PRFilter struct {
+	State     PRState
+	Author    string
+	Label     string
+	Milestone string
+	Reviewer  string
+	Keyword   string
+}
+
+// apiState returns the state to request from the server. Merged pull
+// requests are closed ones, so they are separated client side.
+func (f PRFilter) apiState() gitea.StateType {
+	switch f.State {
+	case PRStateClosed, PRStateMerged:
+		return gitea.StateClosed
+	case PRStateAll:
+		return gitea.StateAll
+	default:
+		return gitea.StateOpen
+	}
+}
+
+// matches checks all criteria that can be decided from the pull request
+// itself. The reviewer is checked separately as it needs extra requests.
+func (f PRFilter) matches(pr *gitea.PullRequest) bool {
+	switch f.State {
+	case PRStateClosed:
+		if pr.HasMerged {
+			return false
+		}
+	case PRStateMerged:
+		if !pr.HasMerged {
+			return false
+		}
+	}
+
+	if f.Author != "" && (pr.Poster == nil || !strings.EqualFold(pr.Poster.UserName, f.Author)) {
+		return false
+	}
+
+	if f.Label != "" && !hasLabel(pr, f.Label) {
+		return false
+	}
+
+	if f.Milestone != "" && (pr.Milestone == nil || !strings.EqualFold(pr.Milestone.Title, f.Milestone)) {
+		return false
+	}
+
+	if f.Keyword != "" {
+		keyword := strings.ToLower(f.Keyword)
+		if !strings.Contains(strings.ToLower(pr.Title), keyword) && !strings.Contains(strings.ToLower(pr.Body), keyword) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasLabel(pr *gitea.PullRequest, name string) bool {
+	for _, label := range pr.Labels {
+		if strings.EqualFold(label.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReviewer reports whether reviewer has reviewed pr or was requested to.
+// This costs a request per pull request: the search API only filters by
+// reviews of the authenticated user, not of arbitrary ones.
+func (c *Client) hasReviewer(owner, repo string, pr *gitea.PullRequest, reviewer string) (bool, error) {
+	reviews, err := c.ListPullReviews(owner, repo, pr.Index)
+	if err != nil {
+		return false, err
+	}
+
+	for _, review := range reviews {
+		if review.Reviewer != nil && strings.EqualFold(review.Reviewer.UserName, reviewer) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (c *Client) filterPullRequests(owner, repo string, prs []*gitea.PullRequest, filter PRFilter) ([]*gitea.PullRequest, error) {
+	filtered := make([]*gitea.PullRequest, 0, len(prs))
+	for _, pr := range prs {
+		if !filter.matches(pr) {
+			continue
+		}
+
+		if filter.Reviewer != "" {
+			ok, err := c.hasReviewer(owner, repo, pr, filter.Reviewer)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		filtered = append(filtered, pr)
+	}
+
+	return filtered, nil
+}
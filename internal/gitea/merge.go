@@ -0,0 +1,86 @@
+package gitea
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// MergeStyleFastForwardOnly only merges if the base branch can be fast
+// forwarded. It is supported by Gitea 1.22 and later but missing from the SDK.
+const MergeStyleFastForwardOnly gitea.MergeStyle = "fast-forward-only"
+
+// MergeStyles lists all merge styles in the order they are presented to the
+// user.
+var MergeStyles = []gitea.MergeStyle{
+	gitea.MergeStyleMerge,
+	gitea.MergeStyleRebase,
+	gitea.MergeStyleRebaseMerge,
+	gitea.MergeStyleSquash,
+	MergeStyleFastForwardOnly,
+}
+
+func ParseMergeStyle(s string) (gitea.MergeStyle, error) {
+	for _, style := range MergeStyles {
+		if strings.EqualFold(s, string(style)) {
+			return style, nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid merge style %q, must be one of merge, rebase, rebase-merge, squash, fast-forward-only", s)
+}
+
+// mergeRefusal describes why the server refused a merge with statusCode.
+func mergeRefusal(statusCode int) string {
+	switch statusCode {
+	case http.StatusMethodNotAllowed:
+		return "merge not allowed"
+	case http.StatusConflict:
+		return "merge conflict"
+	case http.StatusForbidden:
+		return "permission denied"
+	default:
+		return "merge failed"
+	}
+}
+
+// GetDefaultMergeStyle returns the merge style preselected for the
+// repository, falling back to a merge commit.
+func (c *Client) GetDefaultMergeStyle(owner, repo string) (gitea.MergeStyle, error) {
+	r, _, err := c.client.GetRepo(owner, repo)
+	if err != nil {
+		return "", err
+	}
+
+	if r.DefaultMergeStyle == "" {
+		return gitea.MergeStyleMerge, nil
+	}
+	return r.DefaultMergeStyle, nil
+}
+
+// MergePullRequest merges a pull request. It reports false if the merge was
+// only scheduled to happen once the status checks succeed. Refusals by the
+// server wrap an *APIError; a status of 405 means the protection rules of
+// the target branch refused it, e.g. for missing approvals or failed status
+// checks.
+//
+// The SDK's MergePullRequest drops the response body of refused merges, so
+// the request is made directly to keep the server's reason.
+func (c *Client) MergePullRequest(owner, repo string, index int64, opt gitea.MergePullRequestOption) (bool, error) {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d/merge", url.PathEscape(owner), url.PathEscape(repo), index)
+	status, err := c.apiRequest(http.MethodPost, path, &opt, nil)
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return false, fmt.Errorf("%s: %w", mergeRefusal(apiErr.StatusCode), err)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return status != http.StatusCreated, nil
+}
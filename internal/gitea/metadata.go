@@ -0,0 +1,63 @@
+package gitea
+
+import (
+	"code.gitea.io/sdk/gitea"
+)
+
+// ListReviewers returns the names of the users whose review can be
+// requested on pull requests of the repository.
+func (c *Client) ListReviewers(owner, repo string) ([]string, error) {
+	users, _, err := c.client.GetReviewers(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return UserNames(users), nil
+}
+
+// ListAssignees returns the names of the collaborators pull requests of the
+// repository can be assigned to.
+func (c *Client) ListAssignees(owner, repo string) ([]string, error) {
+	users, _, err := c.client.GetAssignees(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	return UserNames(users), nil
+}
+
+// ListMilestones returns the open milestones of the repository.
+func (c *Client) ListMilestones(owner, repo string) ([]*gitea.Milestone, error) {
+	pageSize := c.maxPageSize()
+
+	var milestones []*gitea.Milestone
+	for page := 1; ; page++ {
+		batch, _, err := c.client.ListRepoMilestones(owner, repo, gitea.ListMilestoneOption{
+			ListOptions: gitea.ListOptions{Page: page, PageSize: pageSize},
+			State:       gitea.StateOpen,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		milestones = append(milestones, batch...)
+		if len(batch) < pageSize {
+			return milestones, nil
+		}
+	}
+}
+
+// RequestReviewers requests reviews of a pull request from the given users.
+func (c *Client) RequestReviewers(owner, repo string, index int64, reviewers []string) error {
+	_, err := c.client.CreateReviewRequests(owner, repo, index, gitea.PullReviewRequestOptions{Reviewers: reviewers})
+	return err
+}
+
+// UserNames returns the user names of users.
+func UserNames(users []*gitea.User) []string {
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = user.UserName
+	}
+	return names
+}
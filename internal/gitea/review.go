@@ -0,0 +1,34 @@
+package gitea
+
+import (
+	"code.gitea.io/sdk/gitea"
+)
+
+// LineComment returns a review comment on a line of the diff. Context lines
+// exist on both sides and are addressed by their new line number, removed
+// lines by their old one.
+func LineComment(path string, oldLine, newLine int, body string) gitea.CreatePullReviewComment {
+	comment := gitea.CreatePullReviewComment{Path: path, Body: body}
+	if newLine != 0 {
+		comment.NewLineNum = int64(newLine)
+	} else {
+		comment.OldLineNum = int64(oldLine)
+	}
+	return comment
+}
+
+// CreatePullReview submits a review of the current head of a pull request
+// with the given verdict, message and line comments. Only approvals may have
+// neither a message nor comments.
+func (c *Client) CreatePullReview(owner, repo string, index int64, state gitea.ReviewStateType, body string, comments []gitea.CreatePullReviewComment) (*gitea.PullReview, error) {
+	review, _, err := c.client.CreatePullReview(owner, repo, index, gitea.CreatePullReviewOptions{
+		State:    state,
+		Body:     body,
+		Comments: comments,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return review, nil
+}
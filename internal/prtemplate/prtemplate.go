@@ -0,0 +1,174 @@
+// Package prtemplate finds and parses the pull request templates of a
+// repository.
+package prtemplate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// candidates are the single template files in the order Gitea looks for
+// them, the first one found is used.
+var candidates = []string{
+	"PULL_REQUEST_TEMPLATE.md",
+	"pull_request_template.md",
+	".gitea/PULL_REQUEST_TEMPLATE.md",
+	".gitea/pull_request_template.md",
+	".github/PULL_REQUEST_TEMPLATE.md",
+	".github/pull_request_template.md",
+}
+
+// directories may hold several templates to choose from, the first one
+// found is used.
+var directories = []string{
+	".gitea/PULL_REQUEST_TEMPLATE",
+	".gitea/pull_request_template",
+	".github/PULL_REQUEST_TEMPLATE",
+	".github/pull_request_template",
+}
+
+// Template is a pull request template.
+type Template struct {
+	// Path is relative to the repository root
+	Path string
+	// Name and About describe the template, Name defaults to the file name
+	Name  string
+	About string
+	// Title is prepended to the title of new pull requests
+	Title  string
+	Labels []string
+	Body   string
+}
+
+// frontMatter is the YAML header of a template, as used by Gitea's issue
+// templates.
+type frontMatter struct {
+	Name   string   `yaml:"name"`
+	About  string   `yaml:"about"`
+	Title  string   `yaml:"title"`
+	Labels nameList `yaml:"labels"`
+}
+
+// nameList accepts a YAML list as well as a comma separated string.
+type nameList []string
+
+func (l *nameList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*l = nil
+		for _, name := range strings.Split(node.Value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				*l = append(*l, name)
+			}
+		}
+		return nil
+	}
+
+	var names []string
+	if err := node.Decode(&names); err != nil {
+		return err
+	}
+	*l = names
+	return nil
+}
+
+// Find returns the templates in the worktree at root: the template Gitea
+// would use, followed by those of the first template directory sorted by
+// name.
+func Find(root string) ([]*Template, error) {
+	var templates []*Template
+
+	for _, candidate := range candidates {
+		data, err := os.ReadFile(filepath.Join(root, candidate))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", candidate, err)
+		}
+
+		template, err := Parse(candidate, data)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+		break
+	}
+
+	for _, dir := range directories {
+		entries, err := os.ReadDir(filepath.Join(root, dir))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+		}
+
+		var names []string
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), ".md") {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			path := dir + "/" + name
+			data, err := os.ReadFile(filepath.Join(root, path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", path, err)
+			}
+
+			template, err := Parse(path, data)
+			if err != nil {
+				return nil, err
+			}
+			templates = append(templates, template)
+		}
+		break
+	}
+
+	return templates, nil
+}
+
+// Parse reads a template with optional YAML front matter between "---"
+// lines.
+func Parse(path string, data []byte) (*Template, error) {
+	template := &Template{
+		Path: path,
+		Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	body := string(data)
+
+	if rest, ok := strings.CutPrefix(body, "---\n"); ok {
+		// The leading newline lets the front matter be empty
+		header, content, found := strings.Cut("\n"+rest, "\n---\n")
+		if !found {
+			header, found = strings.CutSuffix("\n"+rest, "\n---")
+		}
+		if found {
+			var fm frontMatter
+			if err := yaml.Unmarshal([]byte(header), &fm); err != nil {
+				return nil, fmt.Errorf("invalid front matter in %s: %w", path, err)
+			}
+
+			if fm.Name != "" {
+				template.Name = fm.Name
+			}
+			template.About = fm.About
+			template.Title = fm.Title
+			template.Labels = fm.Labels
+			body = content
+		}
+	}
+
+	template.Body = strings.TrimSpace(body)
+	return template, nil
+}
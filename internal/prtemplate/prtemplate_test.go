@@ -0,0 +1,166 @@
+package prtemplate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		data    string
+		want    Template
+		wantErr bool
+	}{
+		{
+			name: "plain template",
+			path: ".gitea/pull_request_template.md",
+			data: "## Summary\n\n- [ ] Tests\n",
+			want: Template{Path: ".gitea/pull_request_template.md", Name: "pull_request_template", Body: "## Summary\n\n- [ ] Tests"},
+		},
+		{
+			name: "front matter",
+			path: ".gitea/PULL_REQUEST_TEMPLATE/bug.md",
+			data: "---\nname: Bug fix\nabout: Fix a reported bug\ntitle: \"fix: \"\nlabels:\n  - bug\n  - needs-review\n---\nFixes #\n",
+			want: Template{
+				Path:   ".gitea/PULL_REQUEST_TEMPLATE/bug.md",
+				Name:   "Bug fix",
+				About:  "Fix a reported bug",
+				Title:  "fix: ",
+				Labels: []string{"bug", "needs-review"},
+				Body:   "Fixes #",
+			},
+		},
+		{
+			name: "comma separated labels",
+			path: "feature.md",
+			data: "---\nlabels: enhancement, , docs\n---\nBody",
+			want: Template{Path: "feature.md", Name: "feature", Labels: []string{"enhancement", "docs"}, Body: "Body"},
+		},
+		{
+			name: "name defaults to the file name",
+			path: "docs.md",
+			data: "---\nabout: Documentation\n---\n",
+			want: Template{Path: "docs.md", Name: "docs", About: "Documentation"},
+		},
+		{
+			name: "empty front matter",
+			path: "empty.md",
+			data: "---\n---\nBody",
+			want: Template{Path: "empty.md", Name: "empty", Body: "Body"},
+		},
+		{
+			name: "front matter without body",
+			path: "header.md",
+			data: "---\nname: Header only\n---",
+			want: Template{Path: "header.md", Name: "Header only"},
+		},
+		{
+			name: "windows line endings",
+			path: "crlf.md",
+			data: "---\r\nname: CRLF\r\n---\r\nLine 1\r\nLine 2\r\n",
+			want: Template{Path: "crlf.md", Name: "CRLF", Body: "Line 1\nLine 2"},
+		},
+		{
+			name: "unterminated front matter is body",
+			path: "rule.md",
+			data: "---\nnot front matter",
+			want: Template{Path: "rule.md", Name: "rule", Body: "---\nnot front matter"},
+		},
+		{
+			name: "horizontal rule in body",
+			path: "rule.md",
+			data: "Intro\n\n---\n\nMore",
+			want: Template{Path: "rule.md", Name: "rule", Body: "Intro\n\n---\n\nMore"},
+		},
+		{
+			name:    "invalid front matter",
+			path:    "broken.md",
+			data:    "---\nname: [unclosed\n---\nBody",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.path, []byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  []string
+	}{
+		{
+			name:  "no templates",
+			files: []string{"README.md"},
+			want:  nil,
+		},
+		{
+			name:  "first single template wins",
+			files: []string{".github/pull_request_template.md", ".gitea/PULL_REQUEST_TEMPLATE.md"},
+			want:  []string{".gitea/PULL_REQUEST_TEMPLATE.md"},
+		},
+		{
+			name:  "directory sorted by name",
+			files: []string{".gitea/PULL_REQUEST_TEMPLATE/feature.md", ".gitea/PULL_REQUEST_TEMPLATE/bug.md", ".gitea/PULL_REQUEST_TEMPLATE/notes.txt"},
+			want:  []string{".gitea/PULL_REQUEST_TEMPLATE/bug.md", ".gitea/PULL_REQUEST_TEMPLATE/feature.md"},
+		},
+		{
+			name:  "single template before directory",
+			files: []string{"PULL_REQUEST_TEMPLATE.md", ".github/PULL_REQUEST_TEMPLATE/a.md"},
+			want:  []string{"PULL_REQUEST_TEMPLATE.md", ".github/PULL_REQUEST_TEMPLATE/a.md"},
+		},
+		{
+			name:  "only the first directory",
+			files: []string{".github/PULL_REQUEST_TEMPLATE/b.md", ".gitea/PULL_REQUEST_TEMPLATE/a.md"},
+			want:  []string{".gitea/PULL_REQUEST_TEMPLATE/a.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, file := range tt.files {
+				path := filepath.Join(root, file)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte("Body of "+file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			templates, err := Find(root)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, template := range templates {
+				got = append(got, template.Path)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Find() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Package topic generates and validates the AGit topics pull requests are
+// created with.
+package topic
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultMaxLength limits generated topics unless the rules set a length.
+const DefaultMaxLength = 50
+
+// minSlugLength is kept for the part after the prefix, even if a long
+// prefix leaves less of the length. Shorter lengths apply as they are.
+const minSlugLength = 8
+
+// maxLength is the longest topic Gitea stores as head branch of a pull
+// request.
+const maxLength = 255
+
+// Rules configure how default topics are generated.
+type Rules struct {
+	// FromTitle generates topics from the title even when on a branch
+	FromTitle bool
+	// Prefix is put in front of generated topics, like "alice/"
+	Prefix string
+	// MaxLength limits generated topics including the prefix, 0 means
+	// DefaultMaxLength. A long prefix still leaves minSlugLength bytes.
+	MaxLength int
+}
+
+// Generate returns the default topic for a pull request with title,
+// created from branch, which is empty for a detached HEAD. Topics are made
+// from the branch name unless the rules ask for the title, falling back to
+// the other one if nothing usable is left.
+func Generate(rules Rules, branch, title string) string {
+	limit := rules.MaxLength
+	if limit <= 0 {
+		limit = DefaultMaxLength
+	}
+	limit = max(limit-len(rules.Prefix), min(limit, minSlugLength))
+
+	fromBranch := slugify(branch, limit)
+	// Titles are prose, lower case reads better in a branch name
+	fromTitle := slugify(strings.ToLower(title), limit)
+
+	slug := fromBranch
+	if rules.FromTitle || slug == "" {
+		slug = fromTitle
+	}
+	if slug == "" {
+		slug = fromBranch
+	}
+	if slug == "" {
+		return ""
+	}
+	return rules.Prefix + slug
+}
+
+// slugify replaces everything but ASCII letters, digits, dots and
+// underscores by dashes and shortens the result to limit bytes.
+func slugify(s string, limit int) string {
+	var b strings.Builder
+	var last rune
+	for _, r := range s {
+		if r > unicode.MaxASCII || !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_') {
+			r = '-'
+		}
+		// Runs of dashes or dots only make topics harder to read and type
+		if (r == '-' || r == '.') && r == last {
+			continue
+		}
+		b.WriteRune(r)
+		last = r
+	}
+
+	slug := strings.Trim(b.String(), "-.")
+	if len(slug) > limit {
+		slug = strings.Trim(slug[:limit], "-.")
+	}
+	return strings.TrimSuffix(slug, ".lock")
+}
+
+// Validate returns an error if Gitea won't accept topic, which has to be a
+// valid branch name.
+func Validate(topic string) error {
+	switch {
+	case topic == "":
+		return fmt.Errorf("topic is required")
+	case topic == "HEAD" || topic == "@":
+		return fmt.Errorf("topic can't be %s", topic)
+	case len(topic) > maxLength:
+		return fmt.Errorf("topic is longer than %d characters", maxLength)
+	case strings.HasPrefix(topic, "-"):
+		return fmt.Errorf("topic can't start with '-'")
+	case strings.HasSuffix(topic, "."):
+		return fmt.Errorf("topic can't end with '.'")
+	}
+
+	for _, r := range topic {
+		if unicode.IsSpace(r) || unicode.IsControl(r) || strings.ContainsRune("~^:?*[\\", r) {
+			return fmt.Errorf("topic can't contain %q", r)
+		}
+	}
+
+	for _, seq := range []string{"..", "@{", "//"} {
+		if strings.Contains(topic, seq) {
+			return fmt.Errorf("topic can't contain '%s'", seq)
+		}
+	}
+
+	for _, part := range strings.Split(topic, "/") {
+		switch {
+		case part == "":
+			return fmt.Errorf("topic can't start or end with '/'")
+		case strings.HasPrefix(part, "."):
+			return fmt.Errorf("parts of the topic can't start with '.'")
+		case strings.HasSuffix(part, ".lock"):
+			return fmt.Errorf("parts of the topic can't end with '.lock'")
+		}
+	}
+
+	return nil
+}
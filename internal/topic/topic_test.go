@@ -0,0 +1,104 @@
+package topic
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  Rules
+		branch string
+		title  string
+		want   string
+	}{
+		{"branch name", Rules{}, "feature/login", "Add login", "feature-login"},
+		{"detached HEAD uses the title", Rules{}, "", "Add login form", "add-login-form"},
+		{"from title", Rules{FromTitle: true}, "main", "Fix: crash on start!", "fix-crash-on-start"},
+		{"from title without title", Rules{FromTitle: true}, "feature", "", "feature"},
+		{"prefix", Rules{Prefix: "alice/"}, "fix-typo", "", "alice/fix-typo"},
+		{"nothing usable", Rules{Prefix: "alice/"}, "", "!!!", ""},
+		{"non-ASCII", Rules{}, "", "Größe ändern", "gr-e-ndern"},
+		{"runs of dashes and dots", Rules{}, "a--b..c", "", "a-b.c"},
+		{"trimmed dashes and dots", Rules{}, ".-hidden-.", "", "hidden"},
+		{"lock suffix", Rules{}, "config.lock", "", "config"},
+		{"underscores and digits", Rules{}, "issue_42", "", "issue_42"},
+		{"default length", Rules{FromTitle: true}, "", strings.Repeat("word ", 20), "word-word-word-word-word-word-word-word-word-word"},
+		{"length includes prefix", Rules{Prefix: "bob/", MaxLength: 12}, "a-very-long-branch", "", "bob/a-very-l"},
+		{"cut at a dash", Rules{MaxLength: 7}, "abcdef-ghi", "", "abcdef"},
+		{"prefix longer than the default length", Rules{Prefix: strings.Repeat("p", 50) + "/"}, "a-very-long-branch", "", strings.Repeat("p", 50) + "/a-very-l"},
+		{"prefix longer than the length", Rules{Prefix: "alice/", MaxLength: 8}, "a-very-long-branch", "", "alice/a-very-l"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Generate(tt.rules, tt.branch, tt.title); got != tt.want {
+				t.Errorf("Generate(%+v, %q, %q) = %q, want %q", tt.rules, tt.branch, tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateIsValid(t *testing.T) {
+	titles := []string{
+		"Fix: crash on start!",
+		"Refactor ../../etc/passwd handling",
+		"Add @{upstream} support",
+		"~^:?*[\\ all the special characters",
+		"Trailing dot.",
+		strings.Repeat("long ", 100),
+	}
+
+	for _, title := range titles {
+		topic := Generate(Rules{FromTitle: true, Prefix: "alice/"}, "", title)
+		if err := Validate(topic); err != nil {
+			t.Errorf("Generate() for title %q = %q, which is invalid: %v", title, topic, err)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		topic   string
+		wantErr bool
+	}{
+		{"feature", false},
+		{"alice/fix-typo", false},
+		{"v1.2.3", false},
+		{"Größe", false},
+		{"", true},
+		{"HEAD", true},
+		{"@", true},
+		{strings.Repeat("a", 256), true},
+		{strings.Repeat("a", 255), false},
+		{"-option", true},
+		{"ends.", true},
+		{"with space", true},
+		{"tab\there", true},
+		{"tilde~1", true},
+		{"caret^", true},
+		{"colon:", true},
+		{"question?", true},
+		{"star*", true},
+		{"bracket[", true},
+		{"back\\slash", true},
+		{"double..dot", true},
+		{"reflog@{1}", true},
+		{"double//slash", true},
+		{"/leading", true},
+		{"trailing/", true},
+		{"alice/.hidden", true},
+		{"branch.lock", true},
+		{"dir.lock/branch", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.topic, func(t *testing.T) {
+			err := Validate(tt.topic)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, want error %v", tt.topic, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,447 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	lgitea "lasergit/internal/gitea"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	spinnerStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("13"))
+
+	statusLineStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10"))
+)
+
+// Screen is a single view of the app. Screens are kept on a stack, the top
+// one receives key presses and is rendered.
+type Screen interface {
+	Init() tea.Cmd
+	Update(msg tea.Msg) (Screen, tea.Cmd)
+	View() string
+}
+
+// Actions are the operations the TUI performs on the repository and the
+// Gitea server. They are called from background commands, never from Update.
+type Actions struct {
+	// Source creates pagers for the state tabs of the list
+	Source PRSource
+	// CurrentBranch returns the checked out branch
+	CurrentBranch func() (string, error)
+	// Checkout fetches pr into a local branch and checks it out, returning
+	// the branch name
+	Checkout func(pr *gitea.PullRequest) (string, error)
+	// PrepareCreate returns the initial values of the create dialog
+	PrepareCreate func() (CreatePROptions, error)
+	// Create pushes a new pull request
+	Create func(result CreatePRResult) (CreatedPR, error)
+	// FindTopicPR returns the open pull request of the current topic, or nil
+	// if there is none, together with the topic
+	FindTopicPR func() (*gitea.PullRequest, string, error)
+	// Update force-pushes HEAD to pr under topic and returns the URL of the
+	// pull request the server reported
+	Update func(pr *gitea.PullRequest, topic string) (string, error)
+	// LoadDetails fetches what the detail screen shows about pr
+	LoadDetails func(pr *gitea.PullRequest) (*PRDetails, error)
+	// LoadDiff returns the unified diff of pr
+	LoadDiff func(pr *gitea.PullRequest) (string, error)
+	// SubmitReview submits review on pr
+	SubmitReview func(pr *gitea.PullRequest, review Review) error
+	// ReplyToThread adds comment to the review thread on its line
+	ReplyToThread func(pr *gitea.PullRequest, comment ReviewComment) error
+	// Comment adds a comment to the conversation of pr
+	Comment func(pr *gitea.PullRequest, body string) error
+	// PrepareMerge returns the initial values of the merge dialog
+	PrepareMerge func(pr *gitea.PullRequest) (gitea.MergePullRequestOption, error)
+	// Merge merges pr, reporting false if the merge is scheduled to happen
+	// when the checks succeed
+	Merge func(pr *gitea.PullRequest, opts gitea.MergePullRequestOption) (bool, error)
+	// PrepareEdit returns what the edit dialog shows for pr
+	PrepareEdit func(pr *gitea.PullRequest) (EditPROptions, error)
+	// Edit applies edit to pr and returns the updated pull request
+	Edit func(pr *gitea.PullRequest, edit lgitea.PullRequestEdit) (*gitea.PullRequest, error)
+}
+
+type pushScreenMsg struct {
+	screen Screen
+}
+
+type popScreenMsg struct{}
+
+type replaceScreenMsg struct {
+	screen Screen
+}
+
+type statusMsg struct {
+	text string
+	err  error
+}
+
+type taskMsg struct {
+	title string
+	run   func() (tea.Cmd, error)
+}
+
+type taskDoneMsg struct {
+	next tea.Cmd
+	err  error
+}
+
+// pushScreen shows screen on top of the current one.
+func pushScreen(screen Screen) tea.Cmd {
+	return func() tea.Msg {
+		return pushScreenMsg{screen: screen}
+	}
+}
+
+// replaceScreen shows screen instead of the current one.
+func replaceScreen(screen Screen) tea.Cmd {
+	return func() tea.Msg {
+		return replaceScreenMsg{screen: screen}
+	}
+}
+
+// popScreen returns to the previous screen, quitting after the last one.
+func popScreen() tea.Msg {
+	return popScreenMsg{}
+}
+
+func setStatus(text string) tea.Cmd {
+	return func() tea.Msg {
+		return statusMsg{text: text}
+	}
+}
+
+// runTask runs fn in the background while a spinner with title is shown.
+// On success the command returned by fn is executed, errors end up in the
+// status line.
+func runTask(title string, fn func() (tea.Cmd, error)) tea.Cmd {
+	return func() tea.Msg {
+		return taskMsg{title: title, run: fn}
+	}
+}
+
+// checkoutPRCmd checks out pr and refreshes the list afterwards.
+func checkoutPRCmd(actions *Actions, pr *gitea.PullRequest) tea.Cmd {
+	return runTask(fmt.Sprintf("Checking out PR #%d...", pr.Index), func() (tea.Cmd, error) {
+		branch, err := actions.Checkout(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		status := fmt.Sprintf("✅ Checked out PR #%d: %s (branch '%s')", pr.Index, pr.Title, branch)
+		return tea.Batch(setStatus(status), refreshList), nil
+	})
+}
+
+// createPRCmd opens the create dialog, which pushes the new pull request on
+// submit and returns to the list.
+func createPRCmd(actions *Actions) tea.Cmd {
+	return runTask("Preparing pull request...", func() (tea.Cmd, error) {
+		opts, err := actions.PrepareCreate()
+		if err != nil {
+			return nil, err
+		}
+
+		submit := func(result CreatePRResult) tea.Cmd {
+			return runTask("Creating pull request...", func() (tea.Cmd, error) {
+				created, err := actions.Create(result)
+				if err != nil {
+					return nil, err
+				}
+
+				status := fmt.Sprintf("✅ Created PR for topic '%s' targeting '%s'", result.Topic, result.Target)
+				if created.Number != 0 {
+					status = fmt.Sprintf("✅ Created PR #%d for topic '%s' targeting '%s'", created.Number, result.Topic, result.Target)
+				}
+				if created.URL != "" {
+					status += " " + created.URL
+				}
+				for _, warning := range created.Warnings {
+					status += " ⚠️  " + warning
+				}
+				return tea.Batch(popScreen, setStatus(status), refreshList), nil
+			})
+		}
+
+		return pushScreen(newCreateScreen(opts, submit)), nil
+	})
+}
+
+// pushPRCmd force-pushes HEAD to the open pull request of the current topic
+// after confirmation, or opens the create dialog if there is none.
+func pushPRCmd(actions *Actions) tea.Cmd {
+	return runTask("Looking up PR for the current topic...", func() (tea.Cmd, error) {
+		pr, topic, err := actions.FindTopicPR()
+		if err != nil {
+			return nil, err
+		}
+		if pr == nil {
+			status := fmt.Sprintf("No open PR for topic '%s'", topic)
+			if topic == "" {
+				status = "No topic for a detached HEAD"
+			}
+			return tea.Batch(setStatus(status), createPRCmd(actions)), nil
+		}
+
+		update := runTask(fmt.Sprintf("Updating PR #%d...", pr.Index), func() (tea.Cmd, error) {
+			url, err := actions.Update(pr, topic)
+			if err != nil {
+				return nil, err
+			}
+
+			status := fmt.Sprintf("✅ Updated PR #%d: %s", pr.Index, pr.Title)
+			if url != "" {
+				status += " " + url
+			}
+			return tea.Batch(setStatus(status), refreshList), nil
+		})
+
+		prompt := fmt.Sprintf("Force-push HEAD to PR #%d (topic '%s')?", pr.Index, topic)
+		return pushScreen(NewConfirmModel(prompt, update)), nil
+	})
+}
+
+// mergePRCmd opens the merge dialog for pr, which merges after
+// confirmation and returns to the list.
+func mergePRCmd(actions *Actions, pr *gitea.PullRequest) tea.Cmd {
+	if pr.State != gitea.StateOpen {
+		return setStatus(fmt.Sprintf("PR #%d is not open", pr.Index))
+	}
+
+	return runTask("Preparing merge...", func() (tea.Cmd, error) {
+		opts, err := actions.PrepareMerge(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		submit := func(opts gitea.MergePullRequestOption) tea.Cmd {
+			merge := runTask(fmt.Sprintf("Merging PR #%d...", pr.Index), func() (tea.Cmd, error) {
+				merged, err := actions.Merge(pr, opts)
+				if err != nil {
+					return nil, err
+				}
+
+				status := fmt.Sprintf("✅ Merged PR #%d: %s", pr.Index, pr.Title)
+				if !merged {
+					status = fmt.Sprintf("⏳ PR #%d will be merged when all checks succeed", pr.Index)
+				}
+				return tea.Batch(popScreen, setStatus(status), refreshList), nil
+			})
+
+			target := ""
+			if pr.Base != nil {
+				target = fmt.Sprintf(" into '%s'", pr.Base.Ref)
+			}
+			prompt := fmt.Sprintf("Merge PR #%d%s using %s?", pr.Index, target, opts.Style)
+			return pushScreen(NewConfirmModel(prompt, merge))
+		}
+
+		return pushScreen(NewMergeModel(pr, opts, submit)), nil
+	})
+}
+
+// editPRCmd opens the edit dialog for pr, which saves the changes and
+// returns to the list.
+func editPRCmd(actions *Actions, pr *gitea.PullRequest) tea.Cmd {
+	return runTask(fmt.Sprintf("Loading PR #%d...", pr.Index), func() (tea.Cmd, error) {
+		opts, err := actions.PrepareEdit(pr)
+		if err != nil {
+			return nil, err
+		}
+
+		submit := func(edit lgitea.PullRequestEdit) tea.Cmd {
+			return runTask(fmt.Sprintf("Saving PR #%d...", pr.Index), func() (tea.Cmd, error) {
+				updated, err := actions.Edit(opts.PR, edit)
+				if err != nil {
+					return nil, err
+				}
+
+				status := fmt.Sprintf("✅ Updated PR #%d: %s", updated.Index, updated.Title)
+				return tea.Batch(popScreen, setStatus(status), refreshList), nil
+			})
+		}
+
+		return pushScreen(NewEditPRModel(opts, submit)), nil
+	})
+}
+
+// toggleStatePRCmd closes pr if it is open and reopens it otherwise, after
+// confirmation.
+func toggleStatePRCmd(actions *Actions, pr *gitea.PullRequest) tea.Cmd {
+	if pr.HasMerged {
+		return setStatus(fmt.Sprintf("PR #%d is already merged", pr.Index))
+	}
+
+	state, verb, done := gitea.StateClosed, "Close", "Closed"
+	if pr.State == gitea.StateClosed {
+		state, verb, done = gitea.StateOpen, "Reopen", "Reopened"
+	}
+
+	task := runTask(fmt.Sprintf("%sing PR #%d...", strings.TrimSuffix(verb, "e"), pr.Index), func() (tea.Cmd, error) {
+		if _, err := actions.Edit(pr, lgitea.PullRequestEdit{State: &state}); err != nil {
+			return nil, err
+		}
+
+		status := fmt.Sprintf("✅ %s PR #%d: %s", done, pr.Index, pr.Title)
+		return tea.Batch(setStatus(status), refreshList), nil
+	})
+
+	prompt := fmt.Sprintf("%s PR #%d: %s?", verb, pr.Index, pr.Title)
+	return pushScreen(NewConfirmModel(prompt, task))
+}
+
+// App is the long-running program holding the screen stack.
+type App struct {
+	stack   []Screen
+	spinner spinner.Model
+	busy    string
+	status  string
+	err     error
+	size    *tea.WindowSizeMsg
+}
+
+func NewApp(root Screen) App {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = spinnerStyle
+
+	return App{
+		stack:   []Screen{root},
+		spinner: s,
+	}
+}
+
+func (a App) Init() tea.Cmd {
+	return a.stack[0].Init()
+}
+
+func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return a, tea.Quit
+		}
+		// Keys are ignored while a task runs, so actions don't overlap
+		if a.busy != "" {
+			return a, nil
+		}
+		a.status, a.err = "", nil
+
+		top := len(a.stack) - 1
+		var cmd tea.Cmd
+		a.stack[top], cmd = a.stack[top].Update(msg)
+		return a, cmd
+
+	case pushScreenMsg:
+		a.stack = append(a.stack, msg.screen)
+		return a, a.initTop()
+
+	case replaceScreenMsg:
+		a.stack[len(a.stack)-1] = msg.screen
+		return a, a.initTop()
+
+	case popScreenMsg:
+		a.stack = a.stack[:len(a.stack)-1]
+		if len(a.stack) == 0 {
+			return a, tea.Quit
+		}
+		return a, nil
+
+	case statusMsg:
+		a.status, a.err = msg.text, msg.err
+		return a, nil
+
+	case taskMsg:
+		a.busy = msg.title
+		run := msg.run
+		return a, tea.Batch(a.spinner.Tick, func() tea.Msg {
+			next, err := run()
+			return taskDoneMsg{next: next, err: err}
+		})
+
+	case taskDoneMsg:
+		a.busy = ""
+		if msg.err != nil {
+			a.err = msg.err
+			return a, nil
+		}
+		return a, msg.next
+
+	case tea.WindowSizeMsg:
+		a.size = &msg
+
+	case spinner.TickMsg:
+		if a.busy == "" {
+			return a, nil
+		}
+		var cmd tea.Cmd
+		a.spinner, cmd = a.spinner.Update(msg)
+		return a, cmd
+	}
+
+	// Everything else, like loaded data and window sizes, goes to all
+	// screens so that screens further down the stack stay up to date
+	var cmds []tea.Cmd
+	for i := range a.stack {
+		var cmd tea.Cmd
+		a.stack[i], cmd = a.stack[i].Update(msg)
+		cmds = append(cmds, cmd)
+	}
+	return a, tea.Batch(cmds...)
+}
+
+// initTop lets a new screen on top lay itself out for the current terminal
+// size and start.
+func (a *App) initTop() tea.Cmd {
+	var cmd tea.Cmd
+	top := len(a.stack) - 1
+	if a.size != nil {
+		a.stack[top], cmd = a.stack[top].Update(*a.size)
+	}
+	return tea.Batch(cmd, a.stack[top].Init())
+}
+
+func (a App) View() string {
+	if len(a.stack) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(a.stack[len(a.stack)-1].View())
+	b.WriteString("\n")
+
+	switch {
+	case a.busy != "":
+		b.WriteString(a.spinner.View() + " " + a.busy)
+	case a.err != nil:
+		b.WriteString(errorStyle.Render("✗ " + a.err.Error()))
+	case a.status != "":
+		b.WriteString(statusLineStyle.Render(a.status))
+	}
+
+	return b.String()
+}
+
+// runScreen runs a program with screen as its only initial screen until the
+// screen stack is empty.
+func runScreen(screen Screen) error {
+	if !lipgloss.HasDarkBackground() {
+		markdownStyle = "light"
+	}
+
+	_, err := tea.NewProgram(NewApp(screen)).Run()
+	return err
+}
+
+// Run starts the interactive pull request browser with the given state tab.
+func Run(actions Actions, state, owner, repo, currentBranch string) error {
+	return runScreen(NewListPRModel(actions, state, owner, repo, currentBranch))
+}
@@ -0,0 +1,77 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ComposeModel edits a free text comment. Saving passes the text to onSave
+// and returns to the previous screen, cancelling just returns.
+type ComposeModel struct {
+	title   string
+	context string
+	input   textarea.Model
+	onSave  func(body string) tea.Cmd
+}
+
+// NewComposeModel creates a compose screen prefilled with body. context is
+// shown above the text, e.g. the line being commented on.
+func NewComposeModel(title, context, body string, onSave func(body string) tea.Cmd) ComposeModel {
+	input := textarea.New()
+	input.Placeholder = "Write a comment..."
+	input.CharLimit = 0
+	input.SetWidth(60)
+	input.SetHeight(8)
+	input.SetValue(body)
+	input.Focus()
+
+	return ComposeModel{
+		title:   title,
+		context: context,
+		input:   input,
+		onSave:  onSave,
+	}
+}
+
+func (m ComposeModel) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+func (m ComposeModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, popScreen
+
+		case "ctrl+s", "ctrl+enter":
+			return m, tea.Sequence(popScreen, m.onSave(strings.TrimSpace(m.input.Value())))
+		}
+
+	case tea.WindowSizeMsg:
+		m.input.SetWidth(msg.Width - 4)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m ComposeModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(m.title))
+	b.WriteString("\n")
+	if m.context != "" {
+		b.WriteString(m.context)
+		b.WriteString("\n\n")
+	}
+	b.WriteString(focusedInputStyle.Render(m.input.View()))
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("ctrl+s: save • esc: cancel"))
+
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var dialogStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("13")).
+	Padding(1, 2).
+	Margin(1, 0)
+
+// ConfirmModel asks a yes/no question and runs onConfirm if the answer is
+// yes. Either way it returns to the previous screen. The questions are about
+// destructive actions, so like the confirmations of the commands it
+// defaults to no.
+type ConfirmModel struct {
+	prompt    string
+	onConfirm tea.Cmd
+	yes       bool
+}
+
+func NewConfirmModel(prompt string, onConfirm tea.Cmd) ConfirmModel {
+	return ConfirmModel{
+		prompt:    prompt,
+		onConfirm: onConfirm,
+	}
+}
+
+func (m ConfirmModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ConfirmModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "y", "Y":
+			return m, tea.Sequence(popScreen, m.onConfirm)
+
+		case "n", "N", "q", "esc":
+			return m, popScreen
+
+		case "tab", "shift+tab", "left", "right", "h", "l":
+			m.yes = !m.yes
+
+		case "enter":
+			if m.yes {
+				return m, tea.Sequence(popScreen, m.onConfirm)
+			}
+			return m, popScreen
+		}
+	}
+
+	return m, nil
+}
+
+func (m ConfirmModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(m.prompt)
+	b.WriteString("\n\n")
+
+	var yesButton, noButton string
+	if m.yes {
+		yesButton = activeButtonStyle.Render("Yes")
+		noButton = buttonStyle.Render("No")
+	} else {
+		yesButton = buttonStyle.Render("Yes")
+		noButton = activeButtonStyle.Render("No")
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, yesButton, "   ", noButton))
+
+	return dialogStyle.Render(b.String()) + "\n" +
+		helpStyle.Render("y: confirm • n/esc: cancel • tab: switch • enter: choose")
+}
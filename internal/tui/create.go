@@ -2,7 +2,12 @@ package tui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
+	"time"
+
+	lgitea "lasergit/internal/gitea"
+	"lasergit/internal/topic"
 
 	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -52,17 +57,129 @@ var (
 	branchInfoStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("10")).
 			Bold(false)
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11"))
+)
+
+// Focusable elements of the create dialog, in tab order.
+const (
+	focusTopic = iota
+	focusTarget
+	focusTitle
+	focusDesc
+	focusReviewers
+	focusAssignees
+	focusLabels
+	focusMilestone
+	focusCreate
+	focusCancel
+	focusCount
 )
 
 type CreatePRModel struct {
-	titleInput   textinput.Model
-	descInput    textarea.Model
-	focused      int
-	topicBranch  string
-	targetBranch string
-	err          error
-	done         bool
-	canceled     bool
+	topicInput      textinput.Model
+	titleInput      textinput.Model
+	descInput       textarea.Model
+	targetPicker    picker
+	reviewersPicker multiPicker
+	assigneesPicker multiPicker
+	labelsPicker    multiPicker
+	milestonePicker multiPicker
+	focused         int
+	topicChosen     bool
+	branch          string
+	topicRules      topic.Rules
+	baseBranch      string
+	commits         []string
+	files           []FileStat
+	editor          string
+	startInEditor   bool
+	draft           *CreatePRResult
+	draftSaved      time.Time
+	saveDraft       func(CreatePRResult)
+	initialTitle    string
+	initialDesc     string
+	validate        func(topic, target string) []Problem
+	problems        []Problem
+	checkedTopic    string
+	checkedTarget   string
+	checking        bool
+	submit          func(CreatePRResult) tea.Cmd
+	err             error
+}
+
+// CreatePROptions holds the initial values shown in the create dialog.
+type CreatePROptions struct {
+	// Topic follows the title as generated by TopicRules from the title or
+	// Branch, which is empty for a detached HEAD, until the user changes
+	// it. A chosen topic is kept as it is.
+	Topic       string
+	TopicChosen bool
+	TopicRules  topic.Rules
+	Branch      string
+	Target      string
+	Title       string
+	Description string
+	// Branches offered in the target branch picker
+	Branches []string
+
+	// Commits are the one line summaries of the commits to be pushed, and
+	// Files the changes they make compared to Target
+	Commits []string
+	Files   []FileStat
+
+	Reviewers []string
+	Assignees []string
+	Labels    []string
+	Milestone string
+	// Templates of the repository, one of them is applied to the other
+	// values before the dialog is shown
+	Templates []PRTemplate
+
+	// Editor is the shell command to compose title and description with,
+	// StartInEditor opens it right away
+	Editor        string
+	StartInEditor bool
+
+	// Draft holds values saved earlier for the topic, the dialog offers to
+	// restore them
+	Draft      *CreatePRResult
+	DraftSaved time.Time
+	// SaveDraft is called with the entered values when the dialog is
+	// cancelled after the title or description were changed
+	SaveDraft func(CreatePRResult)
+
+	// Validate checks whether a pull request for topic can be created
+	// against target, it runs again whenever one of them changes
+	Validate func(topic, target string) []Problem
+
+	// Users, labels and milestones offered in the pickers
+	ReviewerChoices  []string
+	AssigneeChoices  []string
+	LabelChoices     []string
+	MilestoneChoices []string
+}
+
+// Problem is something wrong with a pull request about to be created.
+// Blocking problems keep it from being created.
+type Problem struct {
+	Message  string
+	Blocking bool
+}
+
+// problemsMsg carries the problems found for topic and target.
+type problemsMsg struct {
+	topic    string
+	target   string
+	problems []Problem
+}
+
+// FileStat is the number of lines added and removed in a file.
+type FileStat struct {
+	Path    string
+	Added   int
+	Removed int
 }
 
 type CreatePRResult struct {
@@ -70,121 +187,391 @@ type CreatePRResult struct {
 	Description string
 	Topic       string
 	Target      string
-	Canceled    bool
+	// Reviewers, assignees, labels and milestone are set through the API
+	// once the pull request exists, AGit can't pass them
+	Reviewers []string
+	Assignees []string
+	Labels    []string
+	Milestone string
 }
 
-func NewCreatePRModel(topicBranch, targetBranch string) CreatePRModel {
-	// Title input
+// CreatedPR is the pull request pushed by Actions.Create.
+type CreatedPR struct {
+	// Number and URL are 0 and "" if the pull request couldn't be found
+	// after the push
+	Number int64
+	URL    string
+	// Warnings report what failed after the pull request was created
+	Warnings []string
+}
+
+// NewCreatePRModel creates the create dialog. submit is called with the
+// entered values and returns the command to run, cancelling pops the screen.
+func NewCreatePRModel(opts CreatePROptions, submit func(CreatePRResult) tea.Cmd) CreatePRModel {
+	titleInput := newTitleInput(opts.Title)
+	titleInput.Focus()
+
+	// Templates may have changed the title the topic was generated from
+	if !opts.TopicChosen {
+		opts.Topic = topic.Generate(opts.TopicRules, opts.Branch, opts.Title)
+	}
+	topicInput := textinput.New()
+	topicInput.Placeholder = "Enter AGit topic..."
+	topicInput.CharLimit = 255
+	topicInput.Width = 60
+	topicInput.SetValue(opts.Topic)
+
+	var milestone []string
+	if opts.Milestone != "" {
+		milestone = []string{opts.Milestone}
+	}
+
+	return CreatePRModel{
+		topicInput:      topicInput,
+		titleInput:      titleInput,
+		descInput:       newDescriptionInput(opts.Description),
+		targetPicker:    newPicker(opts.Branches, opts.Target, "Filter branches..."),
+		reviewersPicker: newMultiPicker(opts.ReviewerChoices, opts.Reviewers, "Filter users...", false),
+		assigneesPicker: newMultiPicker(opts.AssigneeChoices, opts.Assignees, "Filter users...", false),
+		labelsPicker:    newMultiPicker(opts.LabelChoices, opts.Labels, "Filter labels...", false),
+		milestonePicker: newMultiPicker(opts.MilestoneChoices, milestone, "Filter milestones...", true),
+		focused:         focusTitle,
+		topicChosen:     opts.TopicChosen,
+		branch:          opts.Branch,
+		topicRules:      opts.TopicRules,
+		baseBranch:      opts.Target,
+		commits:         opts.Commits,
+		files:           opts.Files,
+		editor:          opts.Editor,
+		startInEditor:   opts.StartInEditor,
+		draft:           opts.Draft,
+		draftSaved:      opts.DraftSaved,
+		saveDraft:       opts.SaveDraft,
+		initialTitle:    opts.Title,
+		initialDesc:     opts.Description,
+		validate:        opts.Validate,
+		checkedTopic:    opts.Topic,
+		checkedTarget:   opts.Target,
+		checking:        opts.Validate != nil,
+		submit:          submit,
+	}
+}
+
+// newTitleInput returns the input for the title of a pull request.
+func newTitleInput(value string) textinput.Model {
 	titleInput := textinput.New()
 	titleInput.Placeholder = "Enter PR title..."
-	titleInput.Focus()
 	titleInput.CharLimit = 100
 	titleInput.Width = 60
+	titleInput.SetValue(value)
+	return titleInput
+}
 
-	// Description textarea (multiline)
+// newDescriptionInput returns the multiline input for the description of a
+// pull request.
+func newDescriptionInput(value string) textarea.Model {
 	descInput := textarea.New()
 	descInput.Placeholder = "Enter PR description (optional)..."
-	descInput.CharLimit = 1000
+	// The length is checked on submit, the limit of push options is in
+	// encoded bytes rather than characters
+	descInput.CharLimit = 0
 	descInput.SetWidth(60)
 	descInput.SetHeight(4)
+	descInput.SetValue(value)
+	return descInput
+}
 
-	return CreatePRModel{
-		titleInput:   titleInput,
-		descInput:    descInput,
-		focused:      0,
-		topicBranch:  topicBranch,
-		targetBranch: targetBranch,
+func (m CreatePRModel) Init() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.checking {
+		cmds = append(cmds, m.checkTarget())
+	}
+	if m.startInEditor {
+		cmds = append(cmds, m.openEditor())
 	}
+	return tea.Batch(cmds...)
 }
 
-func (m CreatePRModel) Init() tea.Cmd {
-	return nil
+// checkTarget validates the pull request for the checked topic and target.
+func (m CreatePRModel) checkTarget() tea.Cmd {
+	validate, topic, target := m.validate, m.checkedTopic, m.checkedTarget
+	return func() tea.Msg {
+		return problemsMsg{topic: topic, target: target, problems: validate(topic, target)}
+	}
+}
+
+// recheck validates the pull request again if the topic or target changed
+// since the last check.
+func (m *CreatePRModel) recheck() tea.Cmd {
+	topic, target := m.topic(), m.targetPicker.Value()
+	if m.validate == nil || (topic == m.checkedTopic && target == m.checkedTarget) {
+		return nil
+	}
+
+	m.checkedTopic, m.checkedTarget = topic, target
+	m.checking = true
+	return m.checkTarget()
+}
+
+func (m CreatePRModel) topic() string {
+	return strings.TrimSpace(m.topicInput.Value())
+}
+
+// followTitle generates the topic from the title again, unless the user
+// chose one.
+func (m *CreatePRModel) followTitle() {
+	if !m.topicChosen {
+		m.topicInput.SetValue(topic.Generate(m.topicRules, m.branch, m.titleInput.Value()))
+	}
 }
 
-func (m CreatePRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+// openEditor composes the title and description in the editor.
+func (m CreatePRModel) openEditor() tea.Cmd {
+	return openEditor(m.editor, m.titleInput.Value(), m.descInput.Value(), m.topic(), m.targetPicker.Value())
+}
+
+func (m CreatePRModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		m.err = nil
+
 		switch msg.String() {
-		case "ctrl+c", "esc":
-			m.canceled = true
-			m.done = true
-			return m, tea.Quit
+		case "esc":
+			return m, m.cancel()
 
 		case "enter":
 			// Handle button actions
-			if m.focused == 2 {
+			if m.focused == focusCreate {
 				// Create PR button
-				m.done = true
-				return m, tea.Quit
-			} else if m.focused == 3 {
+				return m.submitResult()
+			} else if m.focused == focusCancel {
 				// Cancel button
-				m.canceled = true
-				m.done = true
-				return m, tea.Quit
+				return m, m.cancel()
 			}
-			// If we're on title field, move to description
-			if m.focused == 0 {
-				m.nextInput()
+			// If we're on the topic, target picker or title field, move on
+			if m.focused == focusTopic || m.focused == focusTarget || m.focused == focusTitle {
+				return m, m.nextInput()
 			}
 			// If we're on description field, let Enter add newline (handled by textarea)
 
-		case "tab", "shift+tab", "up", "down":
-			if msg.String() == "up" || msg.String() == "shift+tab" {
-				m.prevInput()
-			} else {
-				m.nextInput()
+		case "up", "down":
+			// The pickers use up/down to move through their items
+			if m.focused == focusTarget || m.multiPicker(m.focused) != nil {
+				break
 			}
+			if msg.String() == "up" {
+				return m, m.prevInput()
+			}
+			return m, m.nextInput()
+
+		case "tab":
+			return m, m.nextInput()
 
-			return m, tea.Batch(cmds...)
+		case "shift+tab":
+			return m, m.prevInput()
 
 		case "ctrl+enter":
 			// Ctrl+Enter submits from anywhere
-			m.done = true
-			return m, tea.Quit
+			return m.submitResult()
+
+		case "ctrl+o":
+			return m, m.openEditor()
+
+		case "ctrl+r":
+			if m.draft != nil {
+				m.restoreDraft()
+				return m, m.recheck()
+			}
 		}
 
+	case problemsMsg:
+		// Results for a previous topic or target are outdated
+		if msg.topic == m.checkedTopic && msg.target == m.checkedTarget {
+			m.problems, m.checking = msg.problems, false
+		}
+		return m, nil
+
+	case editorFinishedMsg:
+		switch {
+		case msg.err != nil:
+			m.err = msg.err
+		case msg.title == "":
+			m.err = fmt.Errorf("empty title, nothing changed")
+		default:
+			m.titleInput.SetValue(msg.title)
+			m.descInput.SetValue(msg.description)
+			m.followTitle()
+		}
+		return m, m.recheck()
+
 	case tea.WindowSizeMsg:
+		m.topicInput.Width = msg.Width - 4
 		m.titleInput.Width = msg.Width - 4
 		m.descInput.SetWidth(msg.Width - 4)
 	}
 
 	// Only update the currently focused input
 	var cmd tea.Cmd
-	if m.focused == 0 {
+	switch m.focused {
+	case focusTopic:
+		before := m.topicInput.Value()
+		m.topicInput, cmd = m.topicInput.Update(msg)
+		if m.topicInput.Value() != before {
+			m.topicChosen = true
+		}
+		cmds = append(cmds, cmd)
+	case focusTarget:
+		m.targetPicker, cmd = m.targetPicker.Update(msg)
+		cmds = append(cmds, cmd)
+	case focusTitle:
 		m.titleInput, cmd = m.titleInput.Update(msg)
+		m.followTitle()
 		cmds = append(cmds, cmd)
-	} else if m.focused == 1 {
+	case focusDesc:
 		m.descInput, cmd = m.descInput.Update(msg)
 		cmds = append(cmds, cmd)
+	default:
+		if p := m.multiPicker(m.focused); p != nil {
+			*p, cmd = p.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m CreatePRModel) View() string {
-	if m.done {
-		return ""
+// multiPicker returns the picker of reviewers, assignees, labels or the
+// milestone at focus, or nil if there is none.
+func (m *CreatePRModel) multiPicker(focus int) *multiPicker {
+	switch focus {
+	case focusReviewers:
+		return &m.reviewersPicker
+	case focusAssignees:
+		return &m.assigneesPicker
+	case focusLabels:
+		return &m.labelsPicker
+	case focusMilestone:
+		return &m.milestonePicker
+	}
+	return nil
+}
+
+// cancel closes the dialog, keeping the entered values as draft if the
+// title or description were changed.
+func (m CreatePRModel) cancel() tea.Cmd {
+	result := m.GetResult()
+	if m.saveDraft == nil || (strings.TrimSpace(result.Title) == strings.TrimSpace(m.initialTitle) &&
+		strings.TrimSpace(result.Description) == strings.TrimSpace(m.initialDesc)) {
+		return popScreen
 	}
 
+	return func() tea.Msg {
+		m.saveDraft(result)
+		return popScreenMsg{}
+	}
+}
+
+// restoreDraft replaces the entered values with the saved draft.
+func (m *CreatePRModel) restoreDraft() {
+	d := m.draft
+	if d.Topic != "" {
+		m.topicInput.SetValue(d.Topic)
+		m.topicChosen = true
+	}
+	m.titleInput.SetValue(d.Title)
+	m.descInput.SetValue(d.Description)
+	if d.Target != "" {
+		m.targetPicker.value = d.Target
+	}
+	m.reviewersPicker.selected = slices.Clone(d.Reviewers)
+	m.assigneesPicker.selected = slices.Clone(d.Assignees)
+	m.labelsPicker.selected = slices.Clone(d.Labels)
+	m.milestonePicker.selected = nil
+	if d.Milestone != "" {
+		m.milestonePicker.selected = []string{d.Milestone}
+	}
+	m.draft = nil
+}
+
+func (m CreatePRModel) submitResult() (Screen, tea.Cmd) {
+	result := m.GetResult()
+	if strings.TrimSpace(result.Title) == "" {
+		m.err = fmt.Errorf("title is required")
+		return m, nil
+	}
+	if err := topic.Validate(result.Topic); err != nil {
+		m.err = err
+		return m, nil
+	}
+	if err := lgitea.CheckPushOption("description", result.Description); err != nil {
+		m.err = err
+		return m, nil
+	}
+
+	if recheck := m.recheck(); recheck != nil || m.checking {
+		m.err = fmt.Errorf("still checking the pull request, try again in a moment")
+		return m, recheck
+	}
+	for _, problem := range m.problems {
+		if problem.Blocking {
+			m.err = fmt.Errorf("can't create the pull request: %s", problem.Message)
+			return m, nil
+		}
+	}
+
+	return m, m.submit(result)
+}
+
+func (m CreatePRModel) View() string {
 	var b strings.Builder
 
 	b.WriteString(titleStyle.Render("🚀 Create Pull Request"))
 	b.WriteString("\n\n")
 
+	if m.draft != nil {
+		b.WriteString(mutedStyle.Render(fmt.Sprintf("📝 Draft from %s: %q • ctrl+r: restore", m.draftSaved.Local().Format("2006-01-02 15:04"), m.draft.Title)))
+		b.WriteString("\n\n")
+	}
+
 	// Topic and target branch info
-	b.WriteString(labelStyle.Render("Topic Branch: "))
-	b.WriteString(branchInfoStyle.Render(m.topicBranch))
-	b.WriteString("\n")
+	b.WriteString(labelStyle.Render("Topic: "))
+	if m.focused == focusTopic {
+		b.WriteString("\n")
+		b.WriteString(focusedInputStyle.Render(m.topicInput.View()))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(branchInfoStyle.Render(m.topic()))
+		if !m.topicChosen {
+			b.WriteString(mutedStyle.Render(" (generated)"))
+		}
+		b.WriteString("\n")
+	}
 	b.WriteString(labelStyle.Render("Target Branch: "))
-	b.WriteString(branchInfoStyle.Render(m.targetBranch))
-	b.WriteString("\n\n")
+	if m.focused == focusTarget {
+		b.WriteString("\n")
+		b.WriteString(focusedInputStyle.Render(m.targetPicker.View(true)))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(m.targetPicker.View(false))
+		b.WriteString("\n\n")
+	}
+
+	if view := m.viewProblems(); view != "" {
+		b.WriteString(view)
+		b.WriteString("\n\n")
+	}
+
+	if len(m.commits) > 0 {
+		b.WriteString(m.viewChanges())
+		b.WriteString("\n\n")
+	}
 
 	// Title input
 	b.WriteString(labelStyle.Render("Title:"))
 	b.WriteString("\n")
-	if m.focused == 0 {
+	if m.focused == focusTitle {
 		b.WriteString(focusedInputStyle.Render(m.titleInput.View()))
 	} else {
 		b.WriteString(inputStyle.Render(m.titleInput.View()))
@@ -194,21 +581,43 @@ func (m CreatePRModel) View() string {
 	// Description textarea
 	b.WriteString(labelStyle.Render("Description:"))
 	b.WriteString("\n")
-	if m.focused == 1 {
+	if m.focused == focusDesc {
 		b.WriteString(focusedInputStyle.Render(m.descInput.View()))
 	} else {
 		b.WriteString(inputStyle.Render(m.descInput.View()))
 	}
 	b.WriteString("\n")
 
+	// Metadata pickers
+	for _, field := range []struct {
+		label string
+		focus int
+	}{
+		{"Reviewers: ", focusReviewers},
+		{"Assignees: ", focusAssignees},
+		{"Labels: ", focusLabels},
+		{"Milestone: ", focusMilestone},
+	} {
+		p := m.multiPicker(field.focus)
+		b.WriteString(labelStyle.Render(field.label))
+		if m.focused == field.focus {
+			b.WriteString("\n")
+			b.WriteString(focusedInputStyle.Render(p.View(true)))
+		} else {
+			b.WriteString(p.View(false))
+			b.WriteString("\n")
+		}
+		b.WriteString("\n")
+	}
+
 	// Buttons
 	var createButton, cancelButton string
-	if m.focused == 2 {
+	if m.focused == focusCreate {
 		createButton = activeButtonStyle.Render("Create PR")
 	} else {
 		createButton = buttonStyle.Render("Create PR")
 	}
-	if m.focused == 3 {
+	if m.focused == focusCancel {
 		cancelButton = activeButtonStyle.Render("Cancel")
 	} else {
 		cancelButton = buttonStyle.Render("Cancel")
@@ -218,77 +627,157 @@ func (m CreatePRModel) View() string {
 	b.WriteString(buttonsLine)
 	b.WriteString("\n\n")
 
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteString("\n")
+	}
+
 	// Help
-	b.WriteString(helpStyle.Render("tab: navigate • enter: newline in description • ctrl+enter: submit • esc: cancel"))
+	b.WriteString(helpStyle.Render("tab: navigate • ↑/↓: move in lists • enter: toggle in lists, newline in description • ctrl+o: editor • ctrl+enter: submit • esc: cancel"))
 
 	return b.String()
 }
 
-func (m *CreatePRModel) nextInput() {
-	// Blur current input
-	if m.focused == 0 {
-		m.titleInput.Blur()
-	} else if m.focused == 1 {
-		m.descInput.Blur()
+// maxSummaryLines limits the commits and files listed in the create dialog.
+const maxSummaryLines = 5
+
+// viewChanges summarizes the commits to be pushed and the files they
+// change.
+func (m CreatePRModel) viewChanges() string {
+	var b strings.Builder
+
+	added, removed := 0, 0
+	for _, file := range m.files {
+		added += file.Added
+		removed += file.Removed
 	}
-	
-	m.focused = (m.focused + 1) % 4 // 0: title, 1: desc, 2: create button, 3: cancel button
-	
-	// Focus new input
-	if m.focused == 0 {
-		m.titleInput.Focus()
-	} else if m.focused == 1 {
-		m.descInput.Focus()
+
+	b.WriteString(labelStyle.Render(fmt.Sprintf("Changes against '%s': ", m.baseBranch)))
+	b.WriteString(fmt.Sprintf("%d commit(s), %d file(s) changed, ", len(m.commits), len(m.files)))
+	b.WriteString(addedStyle.Render(fmt.Sprintf("+%d", added)) + " " + removedStyle.Render(fmt.Sprintf("-%d", removed)))
+	if target := m.targetPicker.Value(); target != m.baseBranch {
+		b.WriteString(mutedStyle.Render(fmt.Sprintf(" (not '%s')", target)))
+	}
+
+	for i, commit := range m.commits {
+		if i == maxSummaryLines {
+			b.WriteString("\n" + mutedStyle.Render(fmt.Sprintf("  … %d more commits", len(m.commits)-i)))
+			break
+		}
+		b.WriteString("\n  • " + commit)
+	}
+
+	for i, file := range m.files {
+		if i == maxSummaryLines {
+			b.WriteString("\n" + mutedStyle.Render(fmt.Sprintf("  … %d more files", len(m.files)-i)))
+			break
+		}
+		b.WriteString(fmt.Sprintf("\n  %s %s %s", file.Path,
+			addedStyle.Render(fmt.Sprintf("+%d", file.Added)), removedStyle.Render(fmt.Sprintf("-%d", file.Removed))))
 	}
+
+	return b.String()
 }
 
-func (m *CreatePRModel) prevInput() {
+func (m *CreatePRModel) nextInput() tea.Cmd {
+	return m.focusInput((m.focused + 1) % focusCount)
+}
+
+func (m *CreatePRModel) prevInput() tea.Cmd {
+	return m.focusInput((m.focused - 1 + focusCount) % focusCount)
+}
+
+func (m *CreatePRModel) focusInput(focus int) tea.Cmd {
 	// Blur current input
-	if m.focused == 0 {
+	switch m.focused {
+	case focusTopic:
+		m.topicInput.Blur()
+	case focusTarget:
+		m.targetPicker.Blur()
+	case focusTitle:
 		m.titleInput.Blur()
-	} else if m.focused == 1 {
+	case focusDesc:
 		m.descInput.Blur()
+	default:
+		if p := m.multiPicker(m.focused); p != nil {
+			p.Blur()
+		}
 	}
-	
-	m.focused = (m.focused - 1 + 4) % 4 // 0: title, 1: desc, 2: create button, 3: cancel button
-	
+
+	m.focused = focus
+
+	// Topic and target are checked once they're chosen, not while typing
+	// or browsing
+	recheck := m.recheck()
+
 	// Focus new input
-	if m.focused == 0 {
-		m.titleInput.Focus()
-	} else if m.focused == 1 {
-		m.descInput.Focus()
+	var focusCmd tea.Cmd
+	switch m.focused {
+	case focusTopic:
+		focusCmd = m.topicInput.Focus()
+	case focusTarget:
+		focusCmd = m.targetPicker.Focus()
+	case focusTitle:
+		focusCmd = m.titleInput.Focus()
+	case focusDesc:
+		focusCmd = m.descInput.Focus()
+	default:
+		if p := m.multiPicker(m.focused); p != nil {
+			focusCmd = p.Focus()
+		}
 	}
+	return tea.Batch(recheck, focusCmd)
 }
 
 func (m CreatePRModel) GetResult() CreatePRResult {
 	return CreatePRResult{
 		Title:       m.titleInput.Value(),
 		Description: m.descInput.Value(),
-		Topic:       m.topicBranch,
-		Target:      m.targetBranch,
-		Canceled:    m.canceled,
+		Topic:       m.topic(),
+		Target:      m.targetPicker.Value(),
+		Reviewers:   m.reviewersPicker.Values(),
+		Assignees:   m.assigneesPicker.Values(),
+		Labels:      m.labelsPicker.Values(),
+		Milestone:   strings.Join(m.milestonePicker.Values(), ""),
 	}
 }
 
-func ShowCreatePRDialog(topicBranch, targetBranch string) (*CreatePRResult, error) {
-	model := NewCreatePRModel(topicBranch, targetBranch)
-	program := tea.NewProgram(model)
-	
-	finalModel, err := program.Run()
-	if err != nil {
-		return nil, err
+// viewProblems lists the problems found, blocking ones first.
+func (m CreatePRModel) viewProblems() string {
+	if m.checking {
+		return mutedStyle.Render("Checking the pull request...")
 	}
 
-	createModel := finalModel.(CreatePRModel)
-	result := createModel.GetResult()
-	
-	if result.Canceled {
-		return nil, fmt.Errorf("canceled by user")
+	var lines []string
+	for _, problem := range m.problems {
+		if problem.Blocking {
+			lines = append(lines, errorStyle.Render("✗ "+problem.Message))
+		}
 	}
+	for _, problem := range m.problems {
+		if !problem.Blocking {
+			lines = append(lines, warningStyle.Render("⚠ "+problem.Message))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
 
-	if result.Title == "" {
-		return nil, fmt.Errorf("title is required")
+// ShowCreatePRDialog runs the create dialog on its own and returns the
+// entered values.
+func ShowCreatePRDialog(opts CreatePROptions) (*CreatePRResult, error) {
+	var result *CreatePRResult
+	model := newCreateScreen(opts, func(r CreatePRResult) tea.Cmd {
+		result = &r
+		return popScreen
+	})
+
+	if err := runScreen(model); err != nil {
+		return nil, err
 	}
 
-	return &result, nil
-}
\ No newline at end of file
+	if result == nil {
+		return nil, fmt.Errorf("canceled by user")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,440 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	lgitea "lasergit/internal/gitea"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	fieldStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("14")).
+			Bold(true).
+			Width(12)
+
+	mutedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8"))
+
+	commitHashStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("11"))
+
+	sectionStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("13")).
+			Bold(true).
+			Margin(1, 0, 0, 0)
+
+	threadHeaderStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("12")).
+				Bold(true)
+)
+
+// markdownStyle is the glamour style used for rendering pull request bodies.
+// It is detected once before the program starts, since querying the terminal
+// while Bubble Tea reads from it is unreliable.
+var markdownStyle = "dark"
+
+// PRDetails is everything shown on the detail screen besides the pull
+// request itself.
+type PRDetails struct {
+	// PR is the freshly fetched pull request
+	PR                 *gitea.PullRequest
+	Commits            []*gitea.Commit
+	RequestedReviewers []string
+	Reviews            []*gitea.PullReview
+	// ReviewComments are the code comments of all reviews
+	ReviewComments []*gitea.PullReviewComment
+	// Comments are the comments of the conversation
+	Comments []*gitea.Comment
+}
+
+type prDetailsMsg struct {
+	index   int64
+	details *PRDetails
+	err     error
+}
+
+// DetailModel shows a single pull request in a scrollable viewport.
+type DetailModel struct {
+	actions *Actions
+	pr      *gitea.PullRequest
+	details *PRDetails
+	threads []reviewThread
+	// selected is the index of the thread replies go to
+	selected int
+	// threadOffsets are the lines of the content the threads start at
+	threadOffsets []int
+	err           error
+	viewport      viewport.Model
+	width         int
+}
+
+func NewDetailModel(actions *Actions, pr *gitea.PullRequest) DetailModel {
+	m := DetailModel{
+		actions:  actions,
+		pr:       pr,
+		viewport: viewport.New(80, 20),
+		width:    80,
+	}
+	m.refresh()
+	return m
+}
+
+func (m DetailModel) Init() tea.Cmd {
+	return loadDetailsCmd(m.actions, m.pr)
+}
+
+func loadDetailsCmd(actions *Actions, pr *gitea.PullRequest) tea.Cmd {
+	return func() tea.Msg {
+		details, err := actions.LoadDetails(pr)
+		return prDetailsMsg{index: pr.Index, details: details, err: err}
+	}
+}
+
+func (m DetailModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case prDetailsMsg:
+		if msg.index != m.pr.Index {
+			return m, nil
+		}
+		m.details, m.err = msg.details, msg.err
+		if m.details != nil && m.details.PR != nil {
+			m.pr = m.details.PR
+		}
+		if m.details != nil {
+			m.threads = groupThreads(m.details.ReviewComments)
+		}
+		m.selected = max(min(m.selected, len(m.threads)-1), 0)
+		m.refresh()
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.viewport.Width = msg.Width
+		// Title, help and the app's status line
+		m.viewport.Height = max(msg.Height-7, 3)
+		m.refresh()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "backspace":
+			return m, popScreen
+
+		case "enter":
+			return m, tea.Sequence(popScreen, checkoutPRCmd(m.actions, m.pr))
+
+		case "d":
+			return m, pushScreen(NewDiffModel(m.actions, m.pr))
+
+		case "tab", "]":
+			m.selectThread(m.selected + 1)
+			return m, nil
+
+		case "shift+tab", "[":
+			m.selectThread(m.selected - 1)
+			return m, nil
+
+		case "r":
+			if len(m.threads) > 0 {
+				return m, m.replyToThread(m.threads[m.selected])
+			}
+			return m, nil
+
+		case "c":
+			return m, m.addComment()
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// refresh renders the content into the viewport and remembers where the
+// threads are.
+func (m *DetailModel) refresh() {
+	content, offsets := m.renderContent()
+	m.threadOffsets = offsets
+	m.viewport.SetContent(content)
+}
+
+// selectThread highlights thread i and scrolls it into view.
+func (m *DetailModel) selectThread(i int) {
+	if len(m.threads) == 0 {
+		return
+	}
+	m.selected = (i + len(m.threads)) % len(m.threads)
+	m.refresh()
+	m.viewport.SetYOffset(m.threadOffsets[m.selected])
+}
+
+// replyToThread opens the compose screen for a reply to thread.
+func (m DetailModel) replyToThread(thread reviewThread) tea.Cmd {
+	actions, pr := m.actions, m.pr
+	onSave := func(body string) tea.Cmd {
+		if body == "" {
+			return nil
+		}
+		return runTask("Sending reply...", func() (tea.Cmd, error) {
+			err := actions.ReplyToThread(pr, ReviewComment{Path: thread.path, OldLine: thread.oldLine, NewLine: thread.newLine, Body: body})
+			if err != nil {
+				return nil, err
+			}
+			status := fmt.Sprintf("✅ Replied on %s:%d", thread.path, thread.line())
+			return tea.Batch(setStatus(status), loadDetailsCmd(actions, pr)), nil
+		})
+	}
+
+	context := threadHeaderStyle.Render(fmt.Sprintf("%s:%d", thread.path, thread.line()))
+	if last := thread.comments[len(thread.comments)-1]; last.Reviewer != nil {
+		context += mutedStyle.Render(" — replying to " + last.Reviewer.UserName)
+	}
+	return pushScreen(NewComposeModel("💬 Reply", context, "", onSave))
+}
+
+// addComment opens the compose screen for a comment on the conversation.
+func (m DetailModel) addComment() tea.Cmd {
+	actions, pr := m.actions, m.pr
+	onSave := func(body string) tea.Cmd {
+		if body == "" {
+			return nil
+		}
+		return runTask("Adding comment...", func() (tea.Cmd, error) {
+			if err := actions.Comment(pr, body); err != nil {
+				return nil, err
+			}
+			status := fmt.Sprintf("✅ Commented on PR #%d", pr.Index)
+			return tea.Batch(setStatus(status), loadDetailsCmd(actions, pr)), nil
+		})
+	}
+
+	return pushScreen(NewComposeModel("💬 Comment", fmt.Sprintf("PR #%d: %s", pr.Index, pr.Title), "", onSave))
+}
+
+// renderContent returns the content of the viewport together with the lines
+// the review threads start at.
+func (m DetailModel) renderContent() (string, []int) {
+	pr := m.pr
+	var b strings.Builder
+
+	field := func(name, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(fieldStyle.Render(name))
+		b.WriteString(value)
+		b.WriteString("\n")
+	}
+
+	field("Author", authorStyle.Render(pr.Poster.UserName))
+	field("Status", statusStyle.Render(prStatus(pr))+mergeableInfo(pr))
+	if pr.Base != nil && pr.Head != nil {
+		field("Branches", branchInfoStyle.Render(pr.Base.Ref)+" ← "+branchInfoStyle.Render(pr.Head.Ref))
+	}
+	field("Labels", labelNames(pr.Labels))
+	field("Assignees", strings.Join(lgitea.UserNames(pr.Assignees), ", "))
+	if pr.Milestone != nil {
+		field("Milestone", pr.Milestone.Title)
+	}
+	if m.details != nil {
+		field("Reviewers", strings.Join(m.details.RequestedReviewers, ", "))
+	}
+	if pr.Created != nil {
+		field("Created", pr.Created.Format("2006-01-02 15:04"))
+	}
+	if pr.Updated != nil {
+		field("Updated", pr.Updated.Format("2006-01-02 15:04"))
+	}
+	field("URL", pr.HTMLURL)
+
+	b.WriteString(sectionStyle.Render("Description"))
+	b.WriteString("\n")
+	if strings.TrimSpace(pr.Body) == "" {
+		b.WriteString(mutedStyle.Render("No description provided."))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(renderMarkdown(pr.Body, m.width))
+	}
+
+	b.WriteString(sectionStyle.Render("Commits"))
+	b.WriteString("\n")
+	switch {
+	case m.err != nil:
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Failed to load details: %v", m.err)))
+		b.WriteString("\n")
+		return b.String(), nil
+	case m.details == nil:
+		b.WriteString(mutedStyle.Render("Loading..."))
+		b.WriteString("\n")
+		return b.String(), nil
+	}
+	for _, commit := range m.details.Commits {
+		b.WriteString(commitLine(commit))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(sectionStyle.Render("Conversation"))
+	b.WriteString("\n")
+	timeline := buildTimeline(m.details.Reviews, m.details.Comments)
+	if len(timeline) == 0 {
+		b.WriteString(mutedStyle.Render("No reviews or comments yet."))
+		b.WriteString("\n")
+	}
+	for _, entry := range timeline {
+		b.WriteString(authorStyle.Render(entry.author) + " " + entry.action + mutedStyle.Render(" · "+entry.time.Format("2006-01-02 15:04")))
+		b.WriteString("\n")
+		if strings.TrimSpace(entry.body) != "" {
+			b.WriteString(renderMarkdown(entry.body, m.width))
+		}
+	}
+
+	b.WriteString(sectionStyle.Render("Code comments"))
+	b.WriteString("\n")
+	if len(m.threads) == 0 {
+		b.WriteString(mutedStyle.Render("No code comments."))
+		b.WriteString("\n")
+	}
+	offsets := make([]int, len(m.threads))
+	for i, thread := range m.threads {
+		offsets[i] = strings.Count(b.String(), "\n")
+		b.WriteString(m.renderThread(thread, i == m.selected))
+	}
+
+	return b.String(), offsets
+}
+
+func (m DetailModel) renderThread(thread reviewThread, selected bool) string {
+	var b strings.Builder
+
+	marker := "  "
+	if selected {
+		marker = selectedStyle.Render("▌") + " "
+	}
+	b.WriteString(marker + threadHeaderStyle.Render(fmt.Sprintf("%s:%d", thread.path, thread.line())))
+	if resolver := thread.resolver(); resolver != "" {
+		b.WriteString(mutedStyle.Render(" ✓ resolved by " + resolver))
+	}
+	b.WriteString("\n")
+
+	for _, line := range hunkContext(thread.diffHunk, 3) {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			line = addedStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			line = removedStyle.Render(line)
+		default:
+			line = mutedStyle.Render(line)
+		}
+		b.WriteString("    " + line + "\n")
+	}
+
+	for _, comment := range thread.comments {
+		author := ""
+		if comment.Reviewer != nil {
+			author = comment.Reviewer.UserName
+		}
+		b.WriteString("  " + authorStyle.Render(author) + mutedStyle.Render(" · "+comment.Created.Format("2006-01-02 15:04")))
+		b.WriteString("\n")
+		b.WriteString(renderMarkdown(comment.Body, m.width))
+	}
+
+	return b.String()
+}
+
+func (m DetailModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("✨ PR #%d: %s", m.pr.Index, m.pr.Title)))
+	b.WriteString("\n")
+	b.WriteString(m.viewport.View())
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render(fmt.Sprintf("↑/↓/pgup/pgdn: scroll (%3.f%%) • tab: next thread • r: reply • c: comment • enter: checkout PR • d: diff • q/esc: back", m.viewport.ScrollPercent()*100)))
+
+	return b.String()
+}
+
+// markdownRenderers caches a renderer per word wrap width. Creating one
+// loads the style, which is too slow to do for every comment on every redraw.
+var (
+	markdownRenderersMu sync.Mutex
+	markdownRenderers   = map[int]*glamour.TermRenderer{}
+)
+
+// markdownRenderer returns the renderer wrapping at wrap columns.
+func markdownRenderer(wrap int) (*glamour.TermRenderer, error) {
+	markdownRenderersMu.Lock()
+	defer markdownRenderersMu.Unlock()
+
+	if renderer, ok := markdownRenderers[wrap]; ok {
+		return renderer, nil
+	}
+
+	renderer, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle(markdownStyle),
+		glamour.WithWordWrap(wrap),
+	)
+	if err != nil {
+		return nil, err
+	}
+	markdownRenderers[wrap] = renderer
+	return renderer, nil
+}
+
+// renderMarkdown renders body for the given terminal width, falling back to
+// the raw text if rendering fails.
+func renderMarkdown(body string, width int) string {
+	renderer, err := markdownRenderer(max(width-8, 20))
+	if err != nil {
+		return body + "\n"
+	}
+
+	out, err := renderer.Render(body)
+	if err != nil {
+		return body + "\n"
+	}
+	return out
+}
+
+func mergeableInfo(pr *gitea.PullRequest) string {
+	if pr.State != gitea.StateOpen {
+		return ""
+	}
+	if pr.Mergeable {
+		return " · " + statusStyle.Render("✓ mergeable")
+	}
+	return " · " + errorStyle.Render("✗ not mergeable")
+}
+
+func labelNames(labels []*gitea.Label) string {
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		names[i] = lipgloss.NewStyle().Foreground(lipgloss.Color("#" + strings.TrimPrefix(label.Color, "#"))).Render(label.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func commitLine(commit *gitea.Commit) string {
+	sha, subject, author := "", "", ""
+	if commit.CommitMeta != nil {
+		sha = commit.SHA
+		if len(sha) > 8 {
+			sha = sha[:8]
+		}
+	}
+	if commit.RepoCommit != nil {
+		subject, _, _ = strings.Cut(commit.RepoCommit.Message, "\n")
+		if commit.RepoCommit.Author != nil {
+			author = commit.RepoCommit.Author.Name
+		}
+	}
+
+	return fmt.Sprintf("%s %s %s", commitHashStyle.Render(sha), subject, mutedStyle.Render("— "+author))
+}
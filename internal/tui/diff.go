@@ -0,0 +1,655 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"lasergit/internal/diff"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+var (
+	fileHeaderStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("12")).
+			Bold(true)
+
+	hunkHeaderStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("6"))
+
+	addedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("10"))
+
+	removedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9"))
+
+	pendingCommentStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("11"))
+
+	treeStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderRight(true).
+			BorderForeground(lipgloss.Color("8")).
+			PaddingRight(1)
+)
+
+// maxTreeWidth is the widest the file tree pane gets.
+const maxTreeWidth = 40
+
+type diffLoadedMsg struct {
+	index int64
+	files []*diff.File
+	// highlighted holds the syntax highlighted content of every line, indexed
+	// by file, hunk and line
+	highlighted [][][]string
+	err         error
+}
+
+// pendingComment is a review comment on a diff line that has not been
+// submitted yet.
+type pendingComment struct {
+	file int
+	hunk int
+	line int
+	body string
+}
+
+// reviewCommentMsg saves the pending comment at index comment, or adds it if
+// comment is -1. An empty body deletes the comment.
+type reviewCommentMsg struct {
+	index   int64
+	comment int
+	pending pendingComment
+}
+
+type reviewSubmittedMsg struct {
+	index int64
+}
+
+type diffRowKind int
+
+const (
+	rowFile diffRowKind = iota
+	rowHunk
+	rowLine
+	rowComment
+)
+
+// diffRow is one line on screen. It points into the parsed diff, so that
+// the file, hunk and line under the cursor are always known.
+type diffRow struct {
+	kind diffRowKind
+	file int
+	hunk int
+	line int
+	// comment is the index of the pending comment shown in a rowComment
+	comment int
+}
+
+// DiffModel shows the diff of a pull request with an optional file tree.
+type DiffModel struct {
+	actions     *Actions
+	pr          *gitea.PullRequest
+	files       []*diff.File
+	highlighted [][][]string
+	collapsed   []bool
+	rows        []diffRow
+	comments    []pendingComment
+	cursor      int
+	offset      int
+	showTree    bool
+	loaded      bool
+	err         error
+	width       int
+	height      int
+}
+
+func NewDiffModel(actions *Actions, pr *gitea.PullRequest) DiffModel {
+	return DiffModel{
+		actions:  actions,
+		pr:       pr,
+		showTree: true,
+		width:    80,
+		height:   20,
+	}
+}
+
+func (m DiffModel) Init() tea.Cmd {
+	actions, pr := m.actions, m.pr
+	return func() tea.Msg {
+		text, err := actions.LoadDiff(pr)
+		if err != nil {
+			return diffLoadedMsg{index: pr.Index, err: err}
+		}
+
+		files, err := diff.Parse(text)
+		if err != nil {
+			return diffLoadedMsg{index: pr.Index, err: fmt.Errorf("failed to parse diff: %w", err)}
+		}
+
+		highlighted := make([][][]string, len(files))
+		for i, file := range files {
+			highlighted[i] = highlightFile(file)
+		}
+
+		return diffLoadedMsg{index: pr.Index, files: files, highlighted: highlighted}
+	}
+}
+
+func (m DiffModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case diffLoadedMsg:
+		if msg.index != m.pr.Index {
+			return m, nil
+		}
+		m.loaded = true
+		m.err = msg.err
+		m.files, m.highlighted = msg.files, msg.highlighted
+		m.collapsed = make([]bool, len(m.files))
+		m.buildRows()
+		m.cursor, m.offset = 0, 0
+		return m, nil
+
+	case reviewCommentMsg:
+		if msg.index != m.pr.Index {
+			return m, nil
+		}
+		switch {
+		case msg.comment < 0 && msg.pending.body != "":
+			m.comments = append(m.comments, msg.pending)
+		case msg.comment >= 0 && msg.pending.body != "":
+			m.comments[msg.comment] = msg.pending
+		case msg.comment >= 0:
+			m.comments = append(m.comments[:msg.comment], m.comments[msg.comment+1:]...)
+		}
+		m.buildRows()
+		m.moveTo(m.cursor)
+		return m, nil
+
+	case reviewSubmittedMsg:
+		if msg.index != m.pr.Index {
+			return m, nil
+		}
+		m.comments = nil
+		m.buildRows()
+		m.moveTo(m.cursor)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		// Title, help and the app's status line
+		m.height = max(msg.Height-7, 3)
+		m.moveTo(m.cursor)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "q", "esc", "backspace":
+			if len(m.comments) > 0 {
+				prompt := fmt.Sprintf("Discard %d pending review comment(s)?", len(m.comments))
+				return m, pushScreen(NewConfirmModel(prompt, popScreen))
+			}
+			return m, popScreen
+
+		case "c":
+			return m, m.editComment()
+
+		case "x":
+			if row, ok := m.currentRow(); ok && row.kind == rowComment {
+				index := m.pr.Index
+				return m, func() tea.Msg {
+					return reviewCommentMsg{index: index, comment: row.comment}
+				}
+			}
+
+		case "s":
+			if m.loaded && m.err == nil {
+				return m, pushScreen(NewReviewModel(m.pr, m.reviewComments(), m.submitReview))
+			}
+
+		case "up", "k":
+			m.moveTo(m.cursor - 1)
+
+		case "down", "j":
+			m.moveTo(m.cursor + 1)
+
+		case "pgup":
+			m.moveTo(m.cursor - m.height)
+
+		case "pgdown":
+			m.moveTo(m.cursor + m.height)
+
+		case "ctrl+u":
+			m.moveTo(m.cursor - m.height/2)
+
+		case "ctrl+d":
+			m.moveTo(m.cursor + m.height/2)
+
+		case "g", "home":
+			m.moveTo(0)
+
+		case "G", "end":
+			m.moveTo(len(m.rows) - 1)
+
+		case "n":
+			m.jump(rowHunk, 1)
+
+		case "p":
+			m.jump(rowHunk, -1)
+
+		case "N", "]":
+			m.jump(rowFile, 1)
+
+		case "P", "[":
+			m.jump(rowFile, -1)
+
+		case "enter", " ", "z":
+			if row, ok := m.currentRow(); ok {
+				m.collapsed[row.file] = !m.collapsed[row.file]
+				m.buildRows()
+				m.moveTo(m.fileRow(row.file))
+			}
+
+		case "Z":
+			if row, ok := m.currentRow(); ok {
+				collapse := !m.collapsed[row.file]
+				for i := range m.collapsed {
+					m.collapsed[i] = collapse
+				}
+				m.buildRows()
+				m.moveTo(m.fileRow(row.file))
+			}
+
+		case "t":
+			m.showTree = !m.showTree
+		}
+	}
+
+	return m, nil
+}
+
+// buildRows lays out the visible rows, leaving out the hunks of collapsed
+// files.
+func (m *DiffModel) buildRows() {
+	m.rows = m.rows[:0]
+	for i, file := range m.files {
+		m.rows = append(m.rows, diffRow{kind: rowFile, file: i})
+		if m.collapsed[i] {
+			continue
+		}
+		for j, hunk := range file.Hunks {
+			m.rows = append(m.rows, diffRow{kind: rowHunk, file: i, hunk: j})
+			for k := range hunk.Lines {
+				m.rows = append(m.rows, diffRow{kind: rowLine, file: i, hunk: j, line: k})
+				for c, comment := range m.comments {
+					if comment.file == i && comment.hunk == j && comment.line == k {
+						m.rows = append(m.rows, diffRow{kind: rowComment, file: i, hunk: j, line: k, comment: c})
+					}
+				}
+			}
+		}
+	}
+}
+
+func (m DiffModel) currentRow() (diffRow, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return diffRow{}, false
+	}
+	return m.rows[m.cursor], true
+}
+
+func (m DiffModel) fileRow(file int) int {
+	for i, row := range m.rows {
+		if row.kind == rowFile && row.file == file {
+			return i
+		}
+	}
+	return 0
+}
+
+// editComment opens the compose screen for the comment under the cursor, or
+// for a new comment on the line under the cursor.
+func (m DiffModel) editComment() tea.Cmd {
+	row, ok := m.currentRow()
+	if !ok || (row.kind != rowLine && row.kind != rowComment) {
+		return nil
+	}
+
+	file := m.files[row.file]
+	line := file.Hunks[row.hunk].Lines[row.line]
+	lineNo := line.NewLine
+	if lineNo == 0 {
+		lineNo = line.OldLine
+	}
+	context := fmt.Sprintf("%s:%d\n%s", file.Name(), lineNo, m.highlighted[row.file][row.hunk][row.line])
+
+	comment, body := -1, ""
+	if row.kind == rowComment {
+		comment, body = row.comment, m.comments[row.comment].body
+	}
+
+	index := m.pr.Index
+	onSave := func(body string) tea.Cmd {
+		return func() tea.Msg {
+			return reviewCommentMsg{
+				index:   index,
+				comment: comment,
+				pending: pendingComment{file: row.file, hunk: row.hunk, line: row.line, body: body},
+			}
+		}
+	}
+
+	return pushScreen(NewComposeModel("💬 Review comment", context, body, onSave))
+}
+
+// reviewComments returns the pending comments in the form they are
+// submitted.
+func (m DiffModel) reviewComments() []ReviewComment {
+	comments := make([]ReviewComment, len(m.comments))
+	for i, comment := range m.comments {
+		line := m.files[comment.file].Hunks[comment.hunk].Lines[comment.line]
+		comments[i] = ReviewComment{
+			Path:    m.files[comment.file].Name(),
+			OldLine: line.OldLine,
+			NewLine: line.NewLine,
+			Body:    comment.body,
+		}
+	}
+	return comments
+}
+
+// submitReview submits review in the background and clears the pending
+// comments once it went through.
+func (m DiffModel) submitReview(review Review) tea.Cmd {
+	actions, pr := m.actions, m.pr
+	return runTask("Submitting review...", func() (tea.Cmd, error) {
+		if err := actions.SubmitReview(pr, review); err != nil {
+			return nil, err
+		}
+
+		submitted := func() tea.Msg {
+			return reviewSubmittedMsg{index: pr.Index}
+		}
+		status := fmt.Sprintf("✅ Submitted review of PR #%d with %d comment(s)", pr.Index, len(review.Comments))
+		return tea.Batch(popScreen, submitted, setStatus(status)), nil
+	})
+}
+
+// moveTo places the cursor on row i and scrolls just enough to keep it
+// visible.
+func (m *DiffModel) moveTo(i int) {
+	m.cursor = max(min(i, len(m.rows)-1), 0)
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+	m.offset = max(min(m.offset, len(m.rows)-m.height), 0)
+}
+
+// jump moves the cursor to the next row of kind in direction dir and
+// scrolls it to the top of the screen.
+func (m *DiffModel) jump(kind diffRowKind, dir int) {
+	for i := m.cursor + dir; i >= 0 && i < len(m.rows); i += dir {
+		if m.rows[i].kind == kind {
+			m.cursor = i
+			m.offset = max(min(i, len(m.rows)-m.height), 0)
+			return
+		}
+	}
+}
+
+func (m DiffModel) View() string {
+	var b strings.Builder
+
+	title := fmt.Sprintf("📄 PR #%d: %s", m.pr.Index, m.pr.Title)
+	if m.loaded && m.err == nil {
+		title += " · " + diffSummary(m.files)
+	}
+	b.WriteString(titleStyle.Render(title))
+	b.WriteString("\n")
+
+	switch {
+	case !m.loaded:
+		b.WriteString(mutedStyle.Render("Loading diff..."))
+	case m.err != nil:
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Failed to load diff: %v", m.err)))
+	case len(m.files) == 0:
+		b.WriteString(mutedStyle.Render("No changes."))
+	default:
+		b.WriteString(m.viewBody())
+	}
+	b.WriteString("\n")
+
+	help := "↑/↓: move • n/p: hunk • N/P: file • z/Z: fold • t: file tree • c: comment • s: submit review • q/esc: back"
+	if row, ok := m.currentRow(); ok && row.kind == rowComment {
+		help = "c: edit comment • x: delete comment • s: submit review • q/esc: back"
+	}
+	if len(m.comments) > 0 {
+		help = fmt.Sprintf("%d pending • %s", len(m.comments), help)
+	}
+	b.WriteString(helpStyle.Render(help))
+
+	return b.String()
+}
+
+func (m DiffModel) viewBody() string {
+	width := m.width
+	var tree string
+	if m.showTree {
+		tree = treeStyle.Height(m.height).Render(m.viewTree(min(width/3, maxTreeWidth)))
+		width -= lipgloss.Width(tree)
+	}
+
+	lines := make([]string, 0, m.height)
+	for i := m.offset; i < len(m.rows) && i < m.offset+m.height; i++ {
+		lines = append(lines, m.viewRow(m.rows[i], i == m.cursor, width))
+	}
+	body := strings.Join(lines, "\n")
+
+	if !m.showTree {
+		return body
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, tree, body)
+}
+
+func (m DiffModel) viewTree(width int) string {
+	current := 0
+	if row, ok := m.currentRow(); ok {
+		current = row.file
+	}
+
+	// Keep the current file in view
+	start := max(current-m.height+1, 0)
+	end := min(start+m.height, len(m.files))
+
+	lines := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		file := m.files[i]
+		added, removed := file.Stats()
+		stats := fmt.Sprintf(" +%d -%d", added, removed)
+
+		// Cut long paths from the left, the file name matters most
+		name := file.Name()
+		if overflow := lipgloss.Width(name) + len(stats) + 2 - width; overflow > 0 {
+			name = ansi.TruncateLeft(name, overflow+1, "…")
+		}
+
+		line := foldMarker(m.collapsed[i]) + " " + name
+		if i == current {
+			line = selectedStyle.Render(line)
+		}
+		lines = append(lines, line+addedStyle.Render(fmt.Sprintf(" +%d", added))+removedStyle.Render(fmt.Sprintf(" -%d", removed)))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (m DiffModel) viewRow(row diffRow, selected bool, width int) string {
+	file := m.files[row.file]
+
+	switch row.kind {
+	case rowFile:
+		added, removed := file.Stats()
+		line := foldMarker(m.collapsed[row.file]) + " " + fileHeaderStyle.Render(file.Name()) + " " +
+			addedStyle.Render(fmt.Sprintf("+%d", added)) + " " + removedStyle.Render(fmt.Sprintf("-%d", removed))
+		if info := fileInfo(file); info != "" {
+			line += " " + mutedStyle.Render(info)
+		}
+		if selected {
+			line = selectedStyle.Render("▌") + line
+		} else {
+			line = " " + line
+		}
+		return ansi.Truncate(line, width, "…")
+
+	case rowHunk:
+		line := hunkHeaderStyle.Render(file.Hunks[row.hunk].Header)
+		if selected {
+			line = selectedStyle.Render("▌") + line
+		} else {
+			line = " " + line
+		}
+		return ansi.Truncate(line, width, "…")
+	}
+
+	if row.kind == rowComment {
+		body, _, _ := strings.Cut(m.comments[row.comment].body, "\n")
+		if strings.Contains(m.comments[row.comment].body, "\n") {
+			body += " …"
+		}
+		line := strings.Repeat(" ", 12) + pendingCommentStyle.Render("💬 "+body)
+		if selected {
+			line = selectedStyle.Render("▌") + line[1:]
+		}
+		return ansi.Truncate(line, width, "…")
+	}
+
+	line := file.Hunks[row.hunk].Lines[row.line]
+	gutter := fmt.Sprintf("%4s %4s ", lineNumber(line.OldLine), lineNumber(line.NewLine))
+	if selected {
+		gutter = selectedStyle.Render(gutter)
+	} else {
+		gutter = mutedStyle.Render(gutter)
+	}
+
+	marker := " "
+	switch line.Kind {
+	case diff.Added:
+		marker = addedStyle.Render("+")
+	case diff.Removed:
+		marker = removedStyle.Render("-")
+	}
+
+	content := m.highlighted[row.file][row.hunk][row.line]
+	return ansi.Truncate(gutter+marker+" "+content, width, "…")
+}
+
+func foldMarker(collapsed bool) string {
+	if collapsed {
+		return "▸"
+	}
+	return "▾"
+}
+
+// fileInfo describes changes to a file that have no hunks.
+func fileInfo(file *diff.File) string {
+	switch {
+	case file.IsBinary:
+		return "(binary)"
+	case file.IsNew:
+		return "(new)"
+	case file.IsDelete:
+		return "(deleted)"
+	case file.OldName != "" && file.NewName != "" && file.OldName != file.NewName:
+		return "(renamed from " + file.OldName + ")"
+	}
+	return ""
+}
+
+func lineNumber(n int) string {
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprint(n)
+}
+
+func diffSummary(files []*diff.File) string {
+	added, removed := 0, 0
+	for _, file := range files {
+		a, r := file.Stats()
+		added += a
+		removed += r
+	}
+
+	noun := "files"
+	if len(files) == 1 {
+		noun = "file"
+	}
+	return fmt.Sprintf("%d %s changed, +%d -%d", len(files), noun, added, removed)
+}
+
+// highlightFile syntax highlights the lines of every hunk of file. Each hunk
+// is tokenized as a whole so that constructs spanning lines, like comments,
+// are colored correctly at least within the hunk.
+func highlightFile(file *diff.File) [][]string {
+	lexer := lexers.Match(file.Name())
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	lexer = chroma.Coalesce(lexer)
+
+	styleName := "monokai"
+	if markdownStyle == "light" {
+		styleName = "github"
+	}
+	style := styles.Get(styleName)
+
+	hunks := make([][]string, len(file.Hunks))
+	for i, hunk := range file.Hunks {
+		var src strings.Builder
+		plain := make([]string, len(hunk.Lines))
+		for j, line := range hunk.Lines {
+			plain[j] = strings.ReplaceAll(line.Content, "\t", "    ")
+			src.WriteString(plain[j])
+			src.WriteString("\n")
+		}
+		hunks[i] = plain
+
+		iterator, err := lexer.Tokenise(nil, src.String())
+		if err != nil {
+			continue
+		}
+
+		tokenLines := chroma.SplitTokensIntoLines(iterator.Tokens())
+		highlighted := make([]string, len(plain))
+		for j := range plain {
+			highlighted[j] = plain[j]
+			if j >= len(tokenLines) {
+				continue
+			}
+
+			var out strings.Builder
+			if err := formatters.TTY256.Format(&out, style, chroma.Literator(tokenLines[j]...)); err != nil {
+				continue
+			}
+			highlighted[j] = strings.ReplaceAll(out.String(), "\n", "")
+		}
+		hunks[i] = highlighted
+	}
+
+	return hunks
+}
+
+// ShowDiff runs the diff screen of pr on its own.
+func ShowDiff(actions Actions, pr *gitea.PullRequest) error {
+	return runScreen(NewDiffModel(&actions, pr))
+}
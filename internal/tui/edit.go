@@ -0,0 +1,362 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	lgitea "lasergit/internal/gitea"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dueDateLayout is the format due dates are entered in.
+const dueDateLayout = "2006-01-02"
+
+// EditPROptions holds what the edit dialog shows.
+type EditPROptions struct {
+	// PR is the current state of the pull request
+	PR *gitea.PullRequest
+	// Branches offered in the target branch picker
+	Branches []string
+}
+
+// Focusable elements of the edit dialog, in tab order.
+const (
+	focusEditTitle = iota
+	focusEditDesc
+	focusEditTarget
+	focusEditAssignees
+	focusEditLabels
+	focusEditMilestone
+	focusEditDueDate
+	focusEditSave
+	focusEditCancel
+	focusEditCount
+)
+
+// EditPRModel edits the title, description and metadata of a pull request.
+type EditPRModel struct {
+	pr             *gitea.PullRequest
+	titleInput     textinput.Model
+	descInput      textarea.Model
+	targetPicker   picker
+	assigneesInput textinput.Model
+	labelsInput    textinput.Model
+	milestoneInput textinput.Model
+	dueDateInput   textinput.Model
+	focused        int
+	submit         func(lgitea.PullRequestEdit) tea.Cmd
+	err            error
+}
+
+// NewEditPRModel creates the edit dialog for opts.PR. submit is called with
+// the fields that were changed.
+func NewEditPRModel(opts EditPROptions, submit func(lgitea.PullRequestEdit) tea.Cmd) EditPRModel {
+	pr := opts.PR
+
+	// Existing values may be longer than new pull requests allow
+	titleInput := newTitleInput("")
+	titleInput.CharLimit = 0
+	titleInput.SetValue(pr.Title)
+	titleInput.Focus()
+
+	descInput := newDescriptionInput("")
+	descInput.CharLimit = 0
+	descInput.SetHeight(6)
+	descInput.SetValue(pr.Body)
+
+	return EditPRModel{
+		pr:             pr,
+		titleInput:     titleInput,
+		descInput:      descInput,
+		targetPicker:   newPicker(opts.Branches, baseBranch(pr), "Filter branches..."),
+		assigneesInput: newFieldInput("user1, user2", strings.Join(lgitea.UserNames(pr.Assignees), ", ")),
+		labelsInput:    newFieldInput("bug, enhancement", strings.Join(prLabels(pr), ", ")),
+		milestoneInput: newFieldInput("No milestone", milestoneName(pr)),
+		dueDateInput:   newFieldInput("YYYY-MM-DD", dueDate(pr)),
+		focused:        focusEditTitle,
+		submit:         submit,
+	}
+}
+
+// newFieldInput returns a single line input for a metadata field.
+func newFieldInput(placeholder, value string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.Width = 60
+	input.SetValue(value)
+	return input
+}
+
+func baseBranch(pr *gitea.PullRequest) string {
+	if pr.Base == nil {
+		return ""
+	}
+	return pr.Base.Ref
+}
+
+func prLabels(pr *gitea.PullRequest) []string {
+	names := make([]string, 0, len(pr.Labels))
+	for _, label := range pr.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+func milestoneName(pr *gitea.PullRequest) string {
+	if pr.Milestone == nil {
+		return ""
+	}
+	return pr.Milestone.Title
+}
+
+func dueDate(pr *gitea.PullRequest) string {
+	if pr.Deadline == nil || pr.Deadline.IsZero() {
+		return ""
+	}
+	return pr.Deadline.Local().Format(dueDateLayout)
+}
+
+func sameList(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+func (m EditPRModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m EditPRModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.err = nil
+
+		switch msg.String() {
+		case "esc":
+			return m, popScreen
+
+		case "tab":
+			return m, m.focusInput((m.focused + 1) % focusEditCount)
+
+		case "shift+tab":
+			return m, m.focusInput((m.focused - 1 + focusEditCount) % focusEditCount)
+
+		case "ctrl+s", "ctrl+enter":
+			return m.submitEdit()
+
+		case "enter":
+			switch m.focused {
+			case focusEditSave:
+				return m.submitEdit()
+			case focusEditCancel:
+				return m, popScreen
+			case focusEditDesc:
+				// Newline in the description
+			default:
+				return m, m.focusInput(m.focused + 1)
+			}
+
+		case "up", "down":
+			// The target picker and the description use up/down themselves
+			if m.focused == focusEditTarget || m.focused == focusEditDesc {
+				break
+			}
+			if msg.String() == "up" {
+				return m, m.focusInput((m.focused - 1 + focusEditCount) % focusEditCount)
+			}
+			return m, m.focusInput((m.focused + 1) % focusEditCount)
+
+		case "left", "right":
+			if m.focused == focusEditSave {
+				return m, m.focusInput(focusEditCancel)
+			}
+			if m.focused == focusEditCancel {
+				return m, m.focusInput(focusEditSave)
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.titleInput.Width = msg.Width - 4
+		m.descInput.SetWidth(msg.Width - 4)
+		for _, focus := range []int{focusEditAssignees, focusEditLabels, focusEditMilestone, focusEditDueDate} {
+			m.textInput(focus).Width = msg.Width - 4
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch m.focused {
+	case focusEditDesc:
+		m.descInput, cmd = m.descInput.Update(msg)
+	case focusEditTarget:
+		m.targetPicker, cmd = m.targetPicker.Update(msg)
+	default:
+		if input := m.textInput(m.focused); input != nil {
+			*input, cmd = input.Update(msg)
+		}
+	}
+	return m, cmd
+}
+
+// textInput returns the single line input at focus, or nil if there is
+// none.
+func (m *EditPRModel) textInput(focus int) *textinput.Model {
+	switch focus {
+	case focusEditTitle:
+		return &m.titleInput
+	case focusEditAssignees:
+		return &m.assigneesInput
+	case focusEditLabels:
+		return &m.labelsInput
+	case focusEditMilestone:
+		return &m.milestoneInput
+	case focusEditDueDate:
+		return &m.dueDateInput
+	}
+	return nil
+}
+
+func (m *EditPRModel) focusInput(focus int) tea.Cmd {
+	switch m.focused {
+	case focusEditDesc:
+		m.descInput.Blur()
+	case focusEditTarget:
+		m.targetPicker.Blur()
+	default:
+		if input := m.textInput(m.focused); input != nil {
+			input.Blur()
+		}
+	}
+
+	m.focused = focus
+
+	switch m.focused {
+	case focusEditDesc:
+		return m.descInput.Focus()
+	case focusEditTarget:
+		return m.targetPicker.Focus()
+	default:
+		if input := m.textInput(m.focused); input != nil {
+			return input.Focus()
+		}
+	}
+	return nil
+}
+
+// changes returns the fields that differ from the pull request.
+func (m EditPRModel) changes() (lgitea.PullRequestEdit, error) {
+	var edit lgitea.PullRequestEdit
+
+	title := strings.TrimSpace(m.titleInput.Value())
+	if title == "" {
+		return edit, fmt.Errorf("title is required")
+	}
+	if title != m.pr.Title {
+		edit.Title = &title
+	}
+
+	if desc := m.descInput.Value(); strings.TrimSpace(desc) != strings.TrimSpace(m.pr.Body) {
+		edit.Body = &desc
+	}
+
+	if target := m.targetPicker.Value(); target != "" && target != baseBranch(m.pr) {
+		edit.Base = &target
+	}
+
+	if assignees := lgitea.SplitList(m.assigneesInput.Value()); !sameList(assignees, lgitea.UserNames(m.pr.Assignees)) {
+		edit.Assignees = &assignees
+	}
+
+	if labels := lgitea.SplitList(m.labelsInput.Value()); !sameList(labels, prLabels(m.pr)) {
+		edit.Labels = &labels
+	}
+
+	if milestone := strings.TrimSpace(m.milestoneInput.Value()); milestone != milestoneName(m.pr) {
+		edit.Milestone = &milestone
+	}
+
+	if due := strings.TrimSpace(m.dueDateInput.Value()); due != dueDate(m.pr) {
+		var date time.Time
+		if due != "" {
+			var err error
+			if date, err = time.ParseInLocation(dueDateLayout, due, time.Local); err != nil {
+				return edit, fmt.Errorf("invalid due date '%s', use YYYY-MM-DD", due)
+			}
+		}
+		edit.Deadline = &date
+	}
+
+	return edit, nil
+}
+
+func (m EditPRModel) submitEdit() (Screen, tea.Cmd) {
+	edit, err := m.changes()
+	if err != nil {
+		m.err = err
+		return m, nil
+	}
+	if edit == (lgitea.PullRequestEdit{}) {
+		return m, tea.Batch(popScreen, setStatus(fmt.Sprintf("No changes to PR #%d", m.pr.Index)))
+	}
+
+	return m, m.submit(edit)
+}
+
+func (m EditPRModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("✏️  Edit PR #%d", m.pr.Index)))
+	b.WriteString("\n\n")
+
+	field := func(label, view string, focused bool) {
+		b.WriteString(labelStyle.Render(label))
+		b.WriteString("\n")
+		if focused {
+			b.WriteString(focusedInputStyle.Render(view))
+		} else {
+			b.WriteString(inputStyle.Render(view))
+		}
+		b.WriteString("\n")
+	}
+
+	field("Title:", m.titleInput.View(), m.focused == focusEditTitle)
+	field("Description:", m.descInput.View(), m.focused == focusEditDesc)
+
+	b.WriteString(labelStyle.Render("Target Branch: "))
+	if m.focused == focusEditTarget {
+		b.WriteString("\n")
+		b.WriteString(focusedInputStyle.Render(m.targetPicker.View(true)))
+		b.WriteString("\n")
+	} else {
+		b.WriteString(m.targetPicker.View(false))
+		b.WriteString("\n\n")
+	}
+
+	field("Assignees:", m.assigneesInput.View(), m.focused == focusEditAssignees)
+	field("Labels:", m.labelsInput.View(), m.focused == focusEditLabels)
+	field("Milestone:", m.milestoneInput.View(), m.focused == focusEditMilestone)
+	field("Due date:", m.dueDateInput.View(), m.focused == focusEditDueDate)
+
+	saveButton, cancelButton := buttonStyle.Render("Save"), buttonStyle.Render("Cancel")
+	if m.focused == focusEditSave {
+		saveButton = activeButtonStyle.Render("Save")
+	}
+	if m.focused == focusEditCancel {
+		cancelButton = activeButtonStyle.Render("Cancel")
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, saveButton, "   ", cancelButton))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("tab: navigate • lists are comma separated, empty to remove • ctrl+s: save • esc: cancel"))
+
+	return b.String()
+}
@@ -0,0 +1,101 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorFinishedMsg carries the title and description written in the
+// editor.
+type editorFinishedMsg struct {
+	title       string
+	description string
+	err         error
+}
+
+// composeMessage returns the file the editor is opened on: the title on the
+// first line and the description below it, like a commit message.
+func composeMessage(title, description, topic, target string) string {
+	var b strings.Builder
+
+	b.WriteString(title)
+	b.WriteString("\n\n")
+	if description != "" {
+		b.WriteString(description)
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("# Enter the title of the pull request on the first line and its\n")
+	b.WriteString("# description below. Lines starting with '# ' are ignored, use '##'\n")
+	b.WriteString("# for headings. An empty title keeps the dialog as it was.\n")
+	b.WriteString("#\n")
+	fmt.Fprintf(&b, "# Topic %s, target %s\n", topic, target)
+
+	return b.String()
+}
+
+// parseMessage returns the title and description of a message written in
+// the editor, with comment lines removed.
+func parseMessage(text string) (title, description string) {
+	var lines []string
+	for _, line := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if line == "#" || strings.HasPrefix(line, "# ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	text = strings.TrimSpace(strings.Join(lines, "\n"))
+	title, description, _ = strings.Cut(text, "\n")
+	return strings.TrimSpace(title), strings.TrimSpace(description)
+}
+
+// openEditor suspends the program to edit title and description with
+// editor, a shell command like git's core.editor.
+func openEditor(editor, title, description, topic, target string) tea.Cmd {
+	if editor == "" {
+		return func() tea.Msg {
+			return editorFinishedMsg{err: fmt.Errorf("no editor configured, set $EDITOR or core.editor")}
+		}
+	}
+
+	file, err := os.CreateTemp("", "PR_EDITMSG-*.md")
+	if err != nil {
+		return func() tea.Msg {
+			return editorFinishedMsg{err: fmt.Errorf("failed to create temporary file: %w", err)}
+		}
+	}
+	path := file.Name()
+
+	_, err = file.WriteString(composeMessage(title, description, topic, target))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg {
+			return editorFinishedMsg{err: fmt.Errorf("failed to write temporary file: %w", err)}
+		}
+	}
+
+	// Run through the shell like git does, editors may come with arguments
+	cmd := exec.Command("sh", "-c", editor+` "$@"`, editor, path)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return editorFinishedMsg{err: fmt.Errorf("editor failed: %w", err)}
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return editorFinishedMsg{err: fmt.Errorf("failed to read temporary file: %w", err)}
+		}
+
+		title, description := parseMessage(string(data))
+		return editorFinishedMsg{title: title, description: description}
+	})
+}
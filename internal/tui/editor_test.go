@@ -0,0 +1,91 @@
+package tui
+
+import (
+	"testing"
+)
+
+func TestParseMessage(t *testing.T) {
+	tests := []struct {
+		name            string
+		text            string
+		wantTitle       string
+		wantDescription string
+	}{
+		{
+			name:      "title only",
+			text:      "Fix typo\n",
+			wantTitle: "Fix typo",
+		},
+		{
+			name:            "title and description",
+			text:            "Fix typo\n\nThe README misspelled the name.\n\nSecond paragraph.\n",
+			wantTitle:       "Fix typo",
+			wantDescription: "The README misspelled the name.\n\nSecond paragraph.",
+		},
+		{
+			name:            "description without blank line",
+			text:            "Fix typo\nDetails\n",
+			wantTitle:       "Fix typo",
+			wantDescription: "Details",
+		},
+		{
+			name:            "comments are removed",
+			text:            "# Leading comment\nFix typo\n\nDetails\n\n# Enter the title\n#\n# Topic fix, target main\n",
+			wantTitle:       "Fix typo",
+			wantDescription: "Details",
+		},
+		{
+			name:            "markdown headings are kept",
+			text:            "Add feature\n\n## Summary\n#hashtag\n",
+			wantTitle:       "Add feature",
+			wantDescription: "## Summary\n#hashtag",
+		},
+		{
+			name:            "surrounding whitespace",
+			text:            "\n\n  Fix typo  \n\n\n  indented\n\n",
+			wantTitle:       "Fix typo",
+			wantDescription: "indented",
+		},
+		{
+			name:            "windows line endings",
+			text:            "Fix typo\r\n\r\nDetails\r\n# comment\r\n",
+			wantTitle:       "Fix typo",
+			wantDescription: "Details",
+		},
+		{
+			name: "only comments",
+			text: "# Enter the title\n#\n",
+		},
+		{
+			name: "empty",
+			text: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			title, description := parseMessage(tt.text)
+			if title != tt.wantTitle || description != tt.wantDescription {
+				t.Errorf("parseMessage() = %q, %q, want %q, %q", title, description, tt.wantTitle, tt.wantDescription)
+			}
+		})
+	}
+}
+
+func TestComposeMessageRoundTrip(t *testing.T) {
+	tests := []struct {
+		title       string
+		description string
+	}{
+		{"Fix typo", ""},
+		{"Add feature", "## Summary\n\nDetails\n\n- [ ] Tests"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		title, description := parseMessage(composeMessage(tt.title, tt.description, "topic", "main"))
+		if title != tt.title || description != tt.description {
+			t.Errorf("round trip of %q, %q = %q, %q", tt.title, tt.description, title, description)
+		}
+	}
+}
@@ -2,9 +2,10 @@ package tui
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
+	"lasergit/internal/git"
+
 	"github.com/charmbracelet/bubbles/table"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -41,26 +42,71 @@ var (
 	currentBranchStyle = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("10")).
 				Bold(true)
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("9")).
+			Bold(true)
+
+	tabStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("8")).
+			Padding(0, 2)
+
+	activeTabStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("13")).
+			Underline(true).
+			Bold(true).
+			Padding(0, 2)
 )
 
+// PRPager loads further pages of pull requests on demand.
+type PRPager interface {
+	HasMore() bool
+	Next() ([]*gitea.PullRequest, error)
+}
+
+// PRSource creates a pager for the pull requests in the given state tab.
+type PRSource func(state string) PRPager
+
+// stateTabs are the states the list can be switched between.
+var stateTabs = []string{"open", "closed", "merged", "all"}
+
+// loadMoreThreshold is how close to the bottom of the table the cursor has
+// to be before the next page is requested.
+const loadMoreThreshold = 3
+
+type prPageMsg struct {
+	pager PRPager
+	prs   []*gitea.PullRequest
+	err   error
+}
+
+// refreshListMsg reloads the list and the current branch.
+type refreshListMsg struct{}
+
+type currentBranchMsg struct {
+	branch string
+}
+
+func refreshList() tea.Msg {
+	return refreshListMsg{}
+}
+
 type ListPRModel struct {
 	table           table.Model
 	prs             []*gitea.PullRequest
+	actions         *Actions
+	state           string
+	pager           PRPager
+	loading         bool
+	err             error
 	owner           string
 	repo            string
 	currentBranch   string
+	currentPRNumber int64
 	currentPRIndex  int
-	selected        int
-	action          string
-	done            bool
 }
 
-type ListPRResult struct {
-	SelectedPR *gitea.PullRequest
-	Action     string // "view", "checkout", "create", "refresh", "quit"
-}
-
-func NewListPRModel(prs []*gitea.PullRequest, owner, repo, currentBranch string) ListPRModel {
+func NewListPRModel(actions Actions, state, owner, repo, currentBranch string) ListPRModel {
 	columns := []table.Column{
 		{Title: "PR", Width: 6},
 		{Title: "Title", Width: 50},
@@ -69,54 +115,8 @@ func NewListPRModel(prs []*gitea.PullRequest, owner, repo, currentBranch string)
 		{Title: "Updated", Width: 12},
 	}
 
-	// Detect if we're currently on a PR branch
-	currentPRRegex := regexp.MustCompile(`^agit-(\d+)$`)
-	var currentPRNumber int64 = -1
-	if matches := currentPRRegex.FindStringSubmatch(currentBranch); len(matches) == 2 {
-		if prNum := matches[1]; prNum != "" {
-			fmt.Sscanf(prNum, "%d", &currentPRNumber)
-		}
-	}
-
-	rows := make([]table.Row, len(prs))
-	for i, pr := range prs {
-		status := "Open"
-		if pr.State == gitea.StateClosed {
-			status = "Closed"
-		} else if pr.Merged != nil && !pr.Merged.IsZero() {
-			status = "Merged"
-		}
-
-		updatedTime := ""
-		if pr.Updated != nil {
-			updatedTime = pr.Updated.Format("2006-01-02")
-		}
-
-		title := pr.Title
-		if len(title) > 47 {
-			title = title[:44] + "..."
-		}
-
-		prNumber := fmt.Sprintf("#%d", pr.Index)
-		author := pr.Poster.UserName
-
-		// Add indicator for current PR
-		if pr.Index == currentPRNumber {
-			prNumber = prNumber + " ●"
-		}
-
-		rows[i] = table.Row{
-			prNumber,
-			title,
-			author,
-			status,
-			updatedTime,
-		}
-	}
-
 	t := table.New(
 		table.WithColumns(columns),
-		table.WithRows(rows),
 		table.WithFocused(true),
 		table.WithHeight(10),
 	)
@@ -126,72 +126,234 @@ func NewListPRModel(prs []*gitea.PullRequest, owner, repo, currentBranch string)
 	s.Selected = selectedStyle
 	t.SetStyles(s)
 
-	// Find the index of the current PR in the list
-	currentPRIdx := -1
-	for i, pr := range prs {
-		if pr.Index == currentPRNumber {
-			currentPRIdx = i
-			break
-		}
-	}
-
 	return ListPRModel{
 		table:           t,
-		prs:             prs,
+		actions:         &actions,
+		state:           state,
+		pager:           actions.Source(state),
 		owner:           owner,
 		repo:            repo,
 		currentBranch:   currentBranch,
-		currentPRIndex:  currentPRIdx,
+		currentPRNumber: prNumberFromBranch(currentBranch),
+		currentPRIndex:  -1,
+	}
+}
+
+// prNumberFromBranch detects if branch is a checked out PR branch and returns
+// its number, or -1.
+func prNumberFromBranch(branch string) int64 {
+	if number, ok := git.PRNumberFromBranch(branch); ok {
+		return number
+	}
+	return -1
+}
+
+func prStatus(pr *gitea.PullRequest) string {
+	if pr.HasMerged || (pr.Merged != nil && !pr.Merged.IsZero()) {
+		return "Merged"
+	} else if pr.State == gitea.StateClosed {
+		return "Closed"
+	}
+	return "Open"
+}
+
+func prRow(pr *gitea.PullRequest, currentPRNumber int64) table.Row {
+	status := prStatus(pr)
+
+	updatedTime := ""
+	if pr.Updated != nil {
+		updatedTime = pr.Updated.Format("2006-01-02")
+	}
+
+	title := pr.Title
+	if len(title) > 47 {
+		title = title[:44] + "..."
+	}
+
+	prNumber := fmt.Sprintf("#%d", pr.Index)
+	author := pr.Poster.UserName
+
+	// Add indicator for current PR
+	if pr.Index == currentPRNumber {
+		prNumber = prNumber + " ●"
+	}
+
+	return table.Row{
+		prNumber,
+		title,
+		author,
+		status,
+		updatedTime,
+	}
+}
+
+// maybeLoadMore requests the next page once the cursor gets close to the
+// bottom of the table.
+func (m *ListPRModel) maybeLoadMore() tea.Cmd {
+	if m.loading || m.pager == nil || !m.pager.HasMore() {
+		return nil
+	}
+	if m.table.Cursor() < len(m.prs)-loadMoreThreshold {
+		return nil
+	}
+
+	m.loading = true
+	pager := m.pager
+	return func() tea.Msg {
+		prs, err := pager.Next()
+		return prPageMsg{pager: pager, prs: prs, err: err}
+	}
+}
+
+// switchState replaces the list with the first page of another state tab.
+// An offset of 0 reloads the current tab.
+func (m *ListPRModel) switchState(offset int) tea.Cmd {
+	current := 0
+	for i, state := range stateTabs {
+		if state == m.state {
+			current = i
+		}
+	}
+
+	m.state = stateTabs[(current+offset+len(stateTabs))%len(stateTabs)]
+	m.pager = m.actions.Source(m.state)
+	m.prs = nil
+	m.currentPRIndex = -1
+	m.loading = false
+	m.err = nil
+	m.table.SetRows(nil)
+	return m.maybeLoadMore()
+}
+
+func (m *ListPRModel) appendPRs(prs []*gitea.PullRequest) {
+	rows := m.table.Rows()
+	for _, pr := range prs {
+		rows = append(rows, prRow(pr, m.currentPRNumber))
+		if pr.Index == m.currentPRNumber {
+			m.currentPRIndex = len(m.prs)
+		}
+		m.prs = append(m.prs, pr)
+	}
+	m.table.SetRows(rows)
+
+	// The cursor is -1 while the table is empty
+	if m.table.Cursor() < 0 && len(rows) > 0 {
+		m.table.SetCursor(0)
 	}
 }
 
 func (m ListPRModel) Init() tea.Cmd {
+	return m.maybeLoadMore()
+}
+
+// selectedPR returns the pull request under the cursor, if any.
+func (m ListPRModel) selectedPR() *gitea.PullRequest {
+	if cursor := m.table.Cursor(); cursor >= 0 && cursor < len(m.prs) {
+		return m.prs[cursor]
+	}
 	return nil
 }
 
-func (m ListPRModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m ListPRModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case refreshListMsg:
+		currentBranch := m.actions.CurrentBranch
+		return m, tea.Batch(m.switchState(0), func() tea.Msg {
+			branch, err := currentBranch()
+			if err != nil {
+				return statusMsg{err: err}
+			}
+			return currentBranchMsg{branch: branch}
+		})
+
+	case currentBranchMsg:
+		m.currentBranch = msg.branch
+		m.currentPRNumber = prNumberFromBranch(msg.branch)
+		prs := m.prs
+		m.prs = nil
+		m.currentPRIndex = -1
+		m.table.SetRows(nil)
+		m.appendPRs(prs)
+		return m, nil
+
+	case prPageMsg:
+		// Drop pages of a tab that is no longer shown
+		if msg.pager != m.pager {
+			return m, nil
+		}
+		m.loading = false
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.appendPRs(msg.prs)
+		return m, m.maybeLoadMore()
+
 	case tea.KeyMsg:
 		switch msg.String() {
-		case "q", "ctrl+c", "esc":
-			m.action = "quit"
-			m.done = true
-			return m, tea.Quit
+		case "q", "esc":
+			return m, popScreen
 
 		case "enter":
-			m.selected = m.table.Cursor()
-			m.action = "checkout"
-			m.done = true
-			return m, tea.Quit
+			if pr := m.selectedPR(); pr != nil {
+				return m, checkoutPRCmd(m.actions, pr)
+			}
+			return m, nil
 
 		case "v":
-			m.selected = m.table.Cursor()
-			m.action = "view"
-			m.done = true
-			return m, tea.Quit
+			if pr := m.selectedPR(); pr != nil {
+				return m, pushScreen(NewDetailModel(m.actions, pr))
+			}
+			return m, nil
+
+		case "d":
+			if pr := m.selectedPR(); pr != nil {
+				return m, pushScreen(NewDiffModel(m.actions, pr))
+			}
+			return m, nil
+
+		case "m":
+			if pr := m.selectedPR(); pr != nil {
+				return m, mergePRCmd(m.actions, pr)
+			}
+			return m, nil
+
+		case "e":
+			if pr := m.selectedPR(); pr != nil {
+				return m, editPRCmd(m.actions, pr)
+			}
+			return m, nil
+
+		case "x":
+			if pr := m.selectedPR(); pr != nil {
+				return m, toggleStatePRCmd(m.actions, pr)
+			}
+			return m, nil
 
 		case "c":
-			m.action = "create"
-			m.done = true
-			return m, tea.Quit
+			return m, createPRCmd(m.actions)
+
+		case "tab", "right", "l":
+			return m, m.switchState(1)
+
+		case "shift+tab", "left", "h":
+			return m, m.switchState(-1)
+
+		case "p":
+			return m, pushPRCmd(m.actions)
 
 		case "r":
-			m.action = "refresh"
-			return m, tea.Quit
+			return m, refreshList
 		}
 	}
 
 	m.table, cmd = m.table.Update(msg)
-	return m, cmd
+	return m, tea.Batch(cmd, m.maybeLoadMore())
 }
 
 func (m ListPRModel) View() string {
-	if m.done {
-		return ""
-	}
-
 	var b strings.Builder
 
 	// Header
@@ -204,18 +366,41 @@ func (m ListPRModel) View() string {
 	b.WriteString(infoStyle.Render(branchInfo))
 	b.WriteString("\n\n")
 
+	// State tabs
+	tabs := make([]string, len(stateTabs))
+	for i, state := range stateTabs {
+		label := strings.ToUpper(state[:1]) + state[1:]
+		if state == m.state {
+			tabs[i] = activeTabStyle.Render(label)
+		} else {
+			tabs[i] = tabStyle.Render(label)
+		}
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, tabs...))
+	b.WriteString("\n")
+
 	// Table
 	b.WriteString(baseStyle.Render(m.table.View()))
 	b.WriteString("\n")
+	if m.loading {
+		b.WriteString(infoStyle.Render("Loading more pull requests..."))
+		b.WriteString("\n")
+	} else if len(m.prs) == 0 && m.err == nil {
+		b.WriteString(infoStyle.Render(fmt.Sprintf("No %s pull requests found", m.state)))
+		b.WriteString("\n")
+	} else if m.err != nil {
+		b.WriteString(errorStyle.Render(fmt.Sprintf("Failed to load more pull requests: %v", m.err)))
+		b.WriteString("\n")
+	}
 
 	// Info section
-	if len(m.prs) > 0 {
-		selected := m.prs[m.table.Cursor()]
+	if cursor := m.table.Cursor(); cursor >= 0 && cursor < len(m.prs) {
+		selected := m.prs[cursor]
 		b.WriteString(infoStyle.Render("Selected PR Details:"))
 		b.WriteString("\n")
 		b.WriteString(fmt.Sprintf("Title: %s\n", selected.Title))
 		b.WriteString(fmt.Sprintf("Author: %s\n", authorStyle.Render(selected.Poster.UserName)))
-		b.WriteString(fmt.Sprintf("Status: %s\n", statusStyle.Render("Open")))
+		b.WriteString(fmt.Sprintf("Status: %s\n", statusStyle.Render(prStatus(selected))))
 		if selected.Updated != nil {
 			b.WriteString(fmt.Sprintf("Updated: %s\n", selected.Updated.Format("2006-01-02 15:04")))
 		}
@@ -230,40 +415,7 @@ func (m ListPRModel) View() string {
 
 	// Help
 	b.WriteString("\n")
-	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: checkout PR • c: create PR • v: view details • r: refresh • q/esc: quit"))
+	b.WriteString(helpStyle.Render("↑/↓: navigate • tab/←/→: switch state • enter: checkout PR • c: create PR • p: push update • v: view details • d: diff • m: merge • e: edit • x: close/reopen • r: refresh • q/esc: quit"))
 
 	return b.String()
 }
-
-func (m ListPRModel) GetResult() ListPRResult {
-	if m.selected >= 0 && m.selected < len(m.prs) && (m.action == "checkout" || m.action == "view") {
-		return ListPRResult{
-			SelectedPR: m.prs[m.selected],
-			Action:     m.action,
-		}
-	}
-	return ListPRResult{
-		SelectedPR: nil,
-		Action:     m.action,
-	}
-}
-
-func ShowPRList(prs []*gitea.PullRequest, owner, repo, currentBranch string) (*ListPRResult, error) {
-	if len(prs) == 0 {
-		fmt.Printf("📋 No open pull requests found for %s/%s\n", owner, repo)
-		return &ListPRResult{Action: "quit"}, nil
-	}
-
-	model := NewListPRModel(prs, owner, repo, currentBranch)
-	program := tea.NewProgram(model)
-	
-	finalModel, err := program.Run()
-	if err != nil {
-		return nil, err
-	}
-
-	listModel := finalModel.(ListPRModel)
-	result := listModel.GetResult()
-	
-	return &result, nil
-}
\ No newline at end of file
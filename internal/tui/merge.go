@@ -0,0 +1,311 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	lgitea "lasergit/internal/gitea"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// mergeStyles are the merge styles offered in the merge dialog, the same
+// ones the merge command accepts.
+var mergeStyles = lgitea.MergeStyles
+
+// mergeStyleLabels describe the merge styles in the merge dialog.
+var mergeStyleLabels = map[gitea.MergeStyle]string{
+	gitea.MergeStyleMerge:            "Create merge commit",
+	gitea.MergeStyleRebase:           "Rebase",
+	gitea.MergeStyleRebaseMerge:      "Rebase, then create merge commit",
+	gitea.MergeStyleSquash:           "Squash",
+	lgitea.MergeStyleFastForwardOnly: "Fast-forward only",
+}
+
+func mergeStyleLabel(style gitea.MergeStyle) string {
+	if label, ok := mergeStyleLabels[style]; ok {
+		return label
+	}
+	return string(style)
+}
+
+// createsCommit reports whether merging with style creates a commit whose
+// message can be chosen.
+func createsCommit(style gitea.MergeStyle) bool {
+	switch style {
+	case gitea.MergeStyleMerge, gitea.MergeStyleRebaseMerge, gitea.MergeStyleSquash:
+		return true
+	}
+	return false
+}
+
+// Focusable elements of the merge dialog, in tab order.
+const (
+	focusStyle = iota
+	focusMergeTitle
+	focusMergeMessage
+	focusDeleteBranch
+	focusAutoMerge
+	focusMergeButton
+	focusMergeCancel
+	focusMergeCount
+)
+
+// MergeModel chooses how a pull request is merged.
+type MergeModel struct {
+	pr           *gitea.PullRequest
+	style        int
+	titleInput   textinput.Model
+	messageInput textarea.Model
+	deleteBranch bool
+	autoMerge    bool
+	focused      int
+	submit       func(gitea.MergePullRequestOption) tea.Cmd
+}
+
+// NewMergeModel creates the merge dialog with the initial values of opts.
+// submit is called with the chosen options.
+func NewMergeModel(pr *gitea.PullRequest, opts gitea.MergePullRequestOption, submit func(gitea.MergePullRequestOption) tea.Cmd) MergeModel {
+	titleInput := textinput.New()
+	titleInput.Placeholder = "Default commit title"
+	titleInput.Width = 60
+	titleInput.SetValue(opts.Title)
+
+	messageInput := textarea.New()
+	messageInput.Placeholder = "Default commit message"
+	messageInput.CharLimit = 0
+	messageInput.SetWidth(60)
+	messageInput.SetHeight(5)
+	messageInput.SetValue(opts.Message)
+
+	style := 0
+	for i, s := range mergeStyles {
+		if s == opts.Style {
+			style = i
+		}
+	}
+
+	return MergeModel{
+		pr:           pr,
+		style:        style,
+		titleInput:   titleInput,
+		messageInput: messageInput,
+		deleteBranch: opts.DeleteBranchAfterMerge,
+		autoMerge:    opts.MergeWhenChecksSucceed,
+		focused:      focusStyle,
+		submit:       submit,
+	}
+}
+
+func (m MergeModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m MergeModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return m, popScreen
+
+		case "tab":
+			return m, m.focusInput(m.nextFocus(1))
+
+		case "shift+tab":
+			return m, m.focusInput(m.nextFocus(-1))
+
+		case "ctrl+s", "ctrl+enter":
+			return m, m.submit(m.options())
+		}
+
+		switch m.focused {
+		case focusStyle:
+			switch msg.String() {
+			case "up", "k":
+				m.style = (m.style - 1 + len(mergeStyles)) % len(mergeStyles)
+			case "down", "j":
+				m.style = (m.style + 1) % len(mergeStyles)
+			case "enter":
+				return m, m.focusInput(m.nextFocus(1))
+			}
+			return m, nil
+
+		case focusMergeTitle:
+			if msg.String() == "enter" {
+				return m, m.focusInput(m.nextFocus(1))
+			}
+
+		case focusDeleteBranch, focusAutoMerge:
+			switch msg.String() {
+			case " ", "enter", "x":
+				if m.focused == focusDeleteBranch {
+					m.deleteBranch = !m.deleteBranch
+				} else {
+					m.autoMerge = !m.autoMerge
+				}
+			case "up":
+				return m, m.focusInput(m.nextFocus(-1))
+			case "down":
+				return m, m.focusInput(m.nextFocus(1))
+			}
+			return m, nil
+
+		case focusMergeButton, focusMergeCancel:
+			switch msg.String() {
+			case "enter":
+				if m.focused == focusMergeCancel {
+					return m, popScreen
+				}
+				return m, m.submit(m.options())
+			case "left", "right", "h", "l":
+				if m.focused == focusMergeButton {
+					return m, m.focusInput(focusMergeCancel)
+				}
+				return m, m.focusInput(focusMergeButton)
+			case "up":
+				return m, m.focusInput(m.nextFocus(-1))
+			}
+			return m, nil
+		}
+
+	case tea.WindowSizeMsg:
+		m.titleInput.Width = msg.Width - 4
+		m.messageInput.SetWidth(msg.Width - 4)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch m.focused {
+	case focusMergeTitle:
+		m.titleInput, cmd = m.titleInput.Update(msg)
+	case focusMergeMessage:
+		m.messageInput, cmd = m.messageInput.Update(msg)
+	}
+	return m, cmd
+}
+
+// nextFocus returns the next element in direction dir, skipping the commit
+// message fields for styles that don't create a commit.
+func (m MergeModel) nextFocus(dir int) int {
+	focus := m.focused
+	for {
+		focus = (focus + dir + focusMergeCount) % focusMergeCount
+		if (focus == focusMergeTitle || focus == focusMergeMessage) && !createsCommit(mergeStyles[m.style]) {
+			continue
+		}
+		return focus
+	}
+}
+
+func (m *MergeModel) focusInput(focus int) tea.Cmd {
+	m.titleInput.Blur()
+	m.messageInput.Blur()
+
+	m.focused = focus
+	switch focus {
+	case focusMergeTitle:
+		return m.titleInput.Focus()
+	case focusMergeMessage:
+		return m.messageInput.Focus()
+	}
+	return nil
+}
+
+// options returns the chosen merge options. The head commit is pinned, so
+// the merge fails if the pull request changed in the meantime.
+func (m MergeModel) options() gitea.MergePullRequestOption {
+	opts := gitea.MergePullRequestOption{
+		Style:                  mergeStyles[m.style],
+		DeleteBranchAfterMerge: m.deleteBranch,
+		MergeWhenChecksSucceed: m.autoMerge,
+	}
+	if m.pr.Head != nil {
+		opts.HeadCommitId = m.pr.Head.Sha
+	}
+	if createsCommit(opts.Style) {
+		opts.Title = strings.TrimSpace(m.titleInput.Value())
+		opts.Message = strings.TrimSpace(m.messageInput.Value())
+	}
+	return opts
+}
+
+func (m MergeModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("🔀 Merge PR #%d: %s", m.pr.Index, m.pr.Title)))
+	b.WriteString("\n")
+	if m.pr.Base != nil && m.pr.Head != nil {
+		b.WriteString(labelStyle.Render("Branches: "))
+		b.WriteString(branchInfoStyle.Render(m.pr.Base.Ref) + " ← " + branchInfoStyle.Render(m.pr.Head.Ref))
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString(labelStyle.Render("Merge style:"))
+	b.WriteString("\n")
+	for i, style := range mergeStyles {
+		line := "( ) " + mergeStyleLabel(style)
+		if i == m.style {
+			line = "(•) " + mergeStyleLabel(style)
+			if m.focused == focusStyle {
+				line = selectedStyle.Render(line)
+			}
+		}
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString("\n")
+
+	if createsCommit(mergeStyles[m.style]) {
+		b.WriteString(labelStyle.Render("Commit title:"))
+		b.WriteString("\n")
+		if m.focused == focusMergeTitle {
+			b.WriteString(focusedInputStyle.Render(m.titleInput.View()))
+		} else {
+			b.WriteString(inputStyle.Render(m.titleInput.View()))
+		}
+		b.WriteString("\n")
+
+		b.WriteString(labelStyle.Render("Commit message:"))
+		b.WriteString("\n")
+		if m.focused == focusMergeMessage {
+			b.WriteString(focusedInputStyle.Render(m.messageInput.View()))
+		} else {
+			b.WriteString(inputStyle.Render(m.messageInput.View()))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(checkbox("Delete head branch after merge", m.deleteBranch, m.focused == focusDeleteBranch))
+	b.WriteString("\n")
+	b.WriteString(checkbox("Merge when checks succeed", m.autoMerge, m.focused == focusAutoMerge))
+	b.WriteString("\n\n")
+
+	mergeButton, cancelButton := buttonStyle.Render("Merge"), buttonStyle.Render("Cancel")
+	if m.focused == focusMergeButton {
+		mergeButton = activeButtonStyle.Render("Merge")
+	}
+	if m.focused == focusMergeCancel {
+		cancelButton = activeButtonStyle.Render("Cancel")
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, mergeButton, "   ", cancelButton))
+	b.WriteString("\n")
+
+	b.WriteString(helpStyle.Render("tab: navigate • ↑/↓: pick style • space: toggle • ctrl+s: merge • esc: cancel"))
+
+	return b.String()
+}
+
+func checkbox(label string, checked, focused bool) string {
+	box := "[ ] "
+	if checked {
+		box = "[x] "
+	}
+	line := box + label
+	if focused {
+		line = selectedStyle.Render(line)
+	}
+	return line
+}
@@ -0,0 +1,307 @@
+package tui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	pickerItemStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("7")).
+			PaddingLeft(2)
+
+	pickerSelectedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("13")).
+				Bold(true)
+)
+
+// picker is a single-choice list that is narrowed down by typing into a
+// filter input. If nothing matches, the typed text itself is the value, so
+// choices missing from the list can still be entered.
+type picker struct {
+	filter  textinput.Model
+	items   []string
+	matches []string
+	cursor  int
+	value   string
+	height  int
+}
+
+func newPicker(items []string, value, placeholder string) picker {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = placeholder
+	filter.Width = 60
+
+	p := picker{
+		filter: filter,
+		items:  items,
+		value:  value,
+		height: 5,
+	}
+	p.applyFilter()
+	return p
+}
+
+func (p *picker) Focus() tea.Cmd {
+	p.filter.SetValue("")
+	p.applyFilter()
+	return p.filter.Focus()
+}
+
+func (p *picker) Blur() {
+	p.filter.Blur()
+}
+
+func (p picker) Value() string {
+	return p.value
+}
+
+func (p picker) Update(msg tea.Msg) (picker, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if p.cursor > 0 {
+				p.cursor--
+				p.value = p.matches[p.cursor]
+			}
+			return p, nil
+		case "down", "ctrl+n":
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+				p.value = p.matches[p.cursor]
+			}
+			return p, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	before := p.filter.Value()
+	p.filter, cmd = p.filter.Update(msg)
+	if p.filter.Value() != before {
+		p.applyFilter()
+	}
+	return p, cmd
+}
+
+// applyFilter recomputes the matching items and keeps the cursor on the
+// current value where possible. The value only changes to a match while a
+// query is typed, without one a value missing from the items is kept and
+// no item is under the cursor.
+func (p *picker) applyFilter() {
+	query := strings.ToLower(strings.TrimSpace(p.filter.Value()))
+
+	p.matches = p.matches[:0]
+	for _, item := range p.items {
+		if strings.Contains(strings.ToLower(item), query) {
+			p.matches = append(p.matches, item)
+		}
+	}
+
+	p.cursor = slices.Index(p.matches, p.value)
+
+	switch {
+	case query != "" && len(p.matches) > 0:
+		p.cursor = max(p.cursor, 0)
+		p.value = p.matches[p.cursor]
+	case query != "":
+		p.value = strings.TrimSpace(p.filter.Value())
+	case p.value == "" && len(p.matches) > 0:
+		p.cursor = 0
+		p.value = p.matches[0]
+	}
+}
+
+func (p picker) View(focused bool) string {
+	if !focused {
+		return branchInfoStyle.Render(p.value)
+	}
+
+	var b strings.Builder
+	b.WriteString(p.filter.View())
+
+	// Scroll the visible window so the cursor stays in view
+	start := 0
+	if p.cursor >= p.height {
+		start = p.cursor - p.height + 1
+	}
+	end := min(start+p.height, len(p.matches))
+
+	for i := start; i < end; i++ {
+		b.WriteString("\n")
+		if i == p.cursor {
+			b.WriteString(pickerSelectedStyle.Render("> " + p.matches[i]))
+		} else {
+			b.WriteString(pickerItemStyle.Render(p.matches[i]))
+		}
+	}
+
+	if p.cursor < 0 && p.value != "" {
+		b.WriteString("\n")
+		b.WriteString(pickerItemStyle.Render("(use \"" + p.value + "\")"))
+	}
+
+	return b.String()
+}
+
+// multiPicker is a list narrowed down by typing into a filter input, from
+// which items are chosen with enter. If nothing matches, enter chooses the
+// typed text itself. With single set, choosing an item replaces the previous
+// choice and choosing it again removes it.
+type multiPicker struct {
+	filter   textinput.Model
+	items    []string
+	matches  []string
+	cursor   int
+	selected []string
+	single   bool
+	height   int
+}
+
+func newMultiPicker(items, selected []string, placeholder string, single bool) multiPicker {
+	filter := textinput.New()
+	filter.Prompt = "/ "
+	filter.Placeholder = placeholder
+	filter.Width = 60
+
+	p := multiPicker{
+		filter:   filter,
+		items:    items,
+		selected: append([]string(nil), selected...),
+		single:   single,
+		height:   5,
+	}
+	p.applyFilter()
+	return p
+}
+
+func (p *multiPicker) Focus() tea.Cmd {
+	p.filter.SetValue("")
+	p.applyFilter()
+	return p.filter.Focus()
+}
+
+func (p *multiPicker) Blur() {
+	p.filter.Blur()
+}
+
+// Values returns the chosen items in the order they were chosen.
+func (p multiPicker) Values() []string {
+	return p.selected
+}
+
+// toggle chooses item or removes it if it was chosen.
+func (p *multiPicker) toggle(item string) {
+	if i := slices.Index(p.selected, item); i >= 0 {
+		p.selected = slices.Delete(slices.Clone(p.selected), i, i+1)
+		return
+	}
+
+	if p.single {
+		p.selected = nil
+	}
+	p.selected = append(p.selected, item)
+}
+
+func (p multiPicker) Update(msg tea.Msg) (multiPicker, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "up", "ctrl+p":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+		case "down", "ctrl+n":
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+			}
+			return p, nil
+		case "enter":
+			if len(p.matches) > 0 {
+				p.toggle(p.matches[p.cursor])
+			} else if query := strings.TrimSpace(p.filter.Value()); query != "" {
+				p.toggle(query)
+				p.filter.SetValue("")
+				p.applyFilter()
+			}
+			return p, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	before := p.filter.Value()
+	p.filter, cmd = p.filter.Update(msg)
+	if p.filter.Value() != before {
+		p.applyFilter()
+	}
+	return p, cmd
+}
+
+// applyFilter recomputes the matching items.
+func (p *multiPicker) applyFilter() {
+	query := strings.ToLower(strings.TrimSpace(p.filter.Value()))
+
+	p.matches = p.matches[:0]
+	for _, item := range p.items {
+		if strings.Contains(strings.ToLower(item), query) {
+			p.matches = append(p.matches, item)
+		}
+	}
+	p.cursor = min(p.cursor, max(len(p.matches)-1, 0))
+}
+
+func (p multiPicker) View(focused bool) string {
+	if !focused {
+		if len(p.selected) == 0 {
+			return mutedStyle.Render("none")
+		}
+		return branchInfoStyle.Render(strings.Join(p.selected, ", "))
+	}
+
+	var b strings.Builder
+	b.WriteString(p.filter.View())
+
+	// Scroll the visible window so the cursor stays in view
+	start := 0
+	if p.cursor >= p.height {
+		start = p.cursor - p.height + 1
+	}
+	end := min(start+p.height, len(p.matches))
+
+	for i := start; i < end; i++ {
+		box := "[ ] "
+		if slices.Contains(p.selected, p.matches[i]) {
+			box = "[x] "
+		}
+
+		b.WriteString("\n")
+		if i == p.cursor {
+			b.WriteString(pickerSelectedStyle.Render("> " + box + p.matches[i]))
+		} else {
+			b.WriteString(pickerItemStyle.Render(box + p.matches[i]))
+		}
+	}
+
+	if query := strings.TrimSpace(p.filter.Value()); len(p.matches) == 0 && query != "" {
+		b.WriteString("\n")
+		b.WriteString(pickerItemStyle.Render("(enter to add \"" + query + "\")"))
+	}
+
+	// Chosen items outside the list, e.g. typed ones, stay visible
+	var others []string
+	for _, s := range p.selected {
+		if !slices.Contains(p.items, s) {
+			others = append(others, s)
+		}
+	}
+	if len(others) > 0 {
+		b.WriteString("\n")
+		b.WriteString(pickerItemStyle.Render("also: " + strings.Join(others, ", ")))
+	}
+
+	return b.String()
+}
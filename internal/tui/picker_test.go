@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPicker(t *testing.T) {
+	branches := []string{"develop", "main", "release/1.0"}
+	typed := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	tests := []struct {
+		name  string
+		value string
+		keys  []tea.KeyMsg
+		want  string
+	}{
+		{"empty value picks the first item", "", nil, "develop"},
+		{"listed value is kept", "main", nil, "main"},
+		{"unlisted value is kept", "feature", nil, "feature"},
+		{"moving down selects an item", "feature", []tea.KeyMsg{{Type: tea.KeyDown}}, "develop"},
+		{"moving up keeps an unlisted value", "feature", []tea.KeyMsg{{Type: tea.KeyUp}}, "feature"},
+		{"typing selects a match", "feature", []tea.KeyMsg{typed("rel")}, "release/1.0"},
+		{"typing without match uses the text", "main", []tea.KeyMsg{typed("hotfix")}, "hotfix"},
+		{"clearing the query keeps the typed text", "main", []tea.KeyMsg{typed("x"), {Type: tea.KeyBackspace}}, "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newPicker(branches, tt.value, "")
+			p.Focus()
+			for _, key := range tt.keys {
+				p, _ = p.Update(key)
+			}
+			if got := p.Value(); got != tt.want {
+				t.Errorf("Value() = %q, want %q", got, tt.want)
+			}
+
+			// Focusing again must not change the value
+			p.Blur()
+			p.Focus()
+			if got := p.Value(); got != tt.want {
+				t.Errorf("Value() after focusing again = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ReviewComment is a comment on a single line of a pull request's diff.
+// OldLine and NewLine are those of the diff line, 0 where it doesn't exist.
+type ReviewComment struct {
+	Path    string
+	OldLine int
+	NewLine int
+	Body    string
+}
+
+// Review is a review composed in the diff screen.
+type Review struct {
+	State    gitea.ReviewStateType
+	Body     string
+	Comments []ReviewComment
+}
+
+// reviewVerdicts are the states a review can be submitted with, in the order
+// they are offered.
+var reviewVerdicts = []struct {
+	state gitea.ReviewStateType
+	label string
+}{
+	{gitea.ReviewStateComment, "Comment"},
+	{gitea.ReviewStateApproved, "Approve"},
+	{gitea.ReviewStateRequestChanges, "Request changes"},
+}
+
+// Focusable elements of the review dialog, in tab order.
+const (
+	focusVerdict = iota
+	focusBody
+	focusReviewCount
+)
+
+// ReviewModel picks the verdict and message of a review before it is
+// submitted together with the pending line comments.
+type ReviewModel struct {
+	pr       *gitea.PullRequest
+	comments []ReviewComment
+	verdict  int
+	body     textarea.Model
+	focused  int
+	submit   func(Review) tea.Cmd
+	err      error
+}
+
+func NewReviewModel(pr *gitea.PullRequest, comments []ReviewComment, submit func(Review) tea.Cmd) ReviewModel {
+	body := textarea.New()
+	body.Placeholder = "Leave a message (optional for approvals)..."
+	body.CharLimit = 0
+	body.SetWidth(60)
+	body.SetHeight(6)
+
+	return ReviewModel{
+		pr:       pr,
+		comments: comments,
+		body:     body,
+		focused:  focusVerdict,
+		submit:   submit,
+	}
+}
+
+func (m ReviewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m ReviewModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.err = nil
+
+		switch msg.String() {
+		case "esc":
+			return m, popScreen
+
+		case "tab", "shift+tab":
+			return m, m.focusInput((m.focused + 1) % focusReviewCount)
+
+		case "ctrl+s", "ctrl+enter":
+			return m.submitReview()
+		}
+
+		if m.focused == focusVerdict {
+			switch msg.String() {
+			case "left", "h":
+				m.verdict = (m.verdict - 1 + len(reviewVerdicts)) % len(reviewVerdicts)
+			case "right", "l":
+				m.verdict = (m.verdict + 1) % len(reviewVerdicts)
+			case "enter":
+				return m.submitReview()
+			}
+			return m, nil
+		}
+
+	case tea.WindowSizeMsg:
+		m.body.SetWidth(msg.Width - 4)
+		return m, nil
+	}
+
+	if m.focused != focusBody {
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.body, cmd = m.body.Update(msg)
+	return m, cmd
+}
+
+func (m *ReviewModel) focusInput(focus int) tea.Cmd {
+	m.focused = focus
+	if focus == focusBody {
+		return m.body.Focus()
+	}
+	m.body.Blur()
+	return nil
+}
+
+func (m ReviewModel) submitReview() (Screen, tea.Cmd) {
+	review := Review{
+		State:    reviewVerdicts[m.verdict].state,
+		Body:     strings.TrimSpace(m.body.Value()),
+		Comments: m.comments,
+	}
+	// Gitea rejects empty reviews unless they approve
+	if review.State != gitea.ReviewStateApproved && review.Body == "" && len(review.Comments) == 0 {
+		m.err = fmt.Errorf("a message is required without line comments")
+		return m, nil
+	}
+
+	return m, m.submit(review)
+}
+
+func (m ReviewModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render(fmt.Sprintf("📝 Review PR #%d: %s", m.pr.Index, m.pr.Title)))
+	b.WriteString("\n")
+
+	switch len(m.comments) {
+	case 0:
+		b.WriteString(mutedStyle.Render("No line comments."))
+	case 1:
+		b.WriteString("1 line comment will be submitted.")
+	default:
+		b.WriteString(fmt.Sprintf("%d line comments will be submitted.", len(m.comments)))
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(labelStyle.Render("Verdict:"))
+	b.WriteString("\n")
+	buttons := make([]string, 0, 2*len(reviewVerdicts))
+	for i, verdict := range reviewVerdicts {
+		style := buttonStyle
+		if i == m.verdict {
+			style = activeButtonStyle
+			if m.focused != focusVerdict {
+				style = style.BorderForeground(lipgloss.Color("8"))
+			}
+		}
+		buttons = append(buttons, style.Render(verdict.label), " ")
+	}
+	b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, buttons...))
+	b.WriteString("\n\n")
+
+	b.WriteString(labelStyle.Render("Message:"))
+	b.WriteString("\n")
+	if m.focused == focusBody {
+		b.WriteString(focusedInputStyle.Render(m.body.View()))
+	} else {
+		b.WriteString(inputStyle.Render(m.body.View()))
+	}
+	b.WriteString("\n")
+
+	if m.err != nil {
+		b.WriteString(errorStyle.Render(m.err.Error()))
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("tab: switch field • ←/→: pick verdict • enter/ctrl+s: submit • esc: cancel"))
+
+	return b.String()
+}
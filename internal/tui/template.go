@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PRTemplate is a pull request template of the repository.
+type PRTemplate struct {
+	Name  string
+	About string
+	// Title is prepended to the title
+	Title  string
+	Labels []string
+	Body   string
+}
+
+// withTemplate returns opts with the body of t prepended to the
+// description, the title prefix of t prepended and its labels added. The
+// description prefilled from the commits is kept below the body.
+func (opts CreatePROptions) withTemplate(t PRTemplate) CreatePROptions {
+	switch {
+	case t.Body == "":
+	case opts.Description == "":
+		opts.Description = t.Body
+	default:
+		opts.Description = t.Body + "\n\n" + opts.Description
+	}
+
+	if t.Title != "" && !strings.HasPrefix(opts.Title, t.Title) {
+		opts.Title = t.Title + opts.Title
+	}
+
+	labels := append([]string(nil), opts.Labels...)
+	for _, label := range t.Labels {
+		if !containsFold(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	opts.Labels = labels
+
+	return opts
+}
+
+func containsFold(items []string, item string) bool {
+	for _, i := range items {
+		if strings.EqualFold(i, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// newCreateScreen returns the create dialog for opts. With several
+// templates the template picker comes first, a single one is applied
+// right away.
+func newCreateScreen(opts CreatePROptions, submit func(CreatePRResult) tea.Cmd) Screen {
+	switch len(opts.Templates) {
+	case 0:
+		return NewCreatePRModel(opts, submit)
+	case 1:
+		return NewCreatePRModel(opts.withTemplate(opts.Templates[0]), submit)
+	}
+
+	return NewTemplatePickerModel(opts.Templates, func(t *PRTemplate) tea.Cmd {
+		withTemplate := opts
+		if t != nil {
+			withTemplate = opts.withTemplate(*t)
+		}
+		return replaceScreen(NewCreatePRModel(withTemplate, submit))
+	})
+}
+
+// TemplatePickerModel lets the user choose one of several pull request
+// templates, or none.
+type TemplatePickerModel struct {
+	templates []PRTemplate
+	cursor    int
+	choose    func(*PRTemplate) tea.Cmd
+}
+
+// NewTemplatePickerModel creates the template picker. choose is called with
+// the chosen template, or nil for none.
+func NewTemplatePickerModel(templates []PRTemplate, choose func(*PRTemplate) tea.Cmd) TemplatePickerModel {
+	return TemplatePickerModel{
+		templates: templates,
+		choose:    choose,
+	}
+}
+
+func (m TemplatePickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m TemplatePickerModel) Update(msg tea.Msg) (Screen, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	// The entry after the templates is "no template"
+	switch key.String() {
+	case "esc", "q":
+		return m, popScreen
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.templates) {
+			m.cursor++
+		}
+	case "enter":
+		if m.cursor == len(m.templates) {
+			return m, m.choose(nil)
+		}
+		return m, m.choose(&m.templates[m.cursor])
+	}
+	return m, nil
+}
+
+func (m TemplatePickerModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("📋 Choose a Pull Request Template"))
+	b.WriteString("\n\n")
+
+	for i := 0; i <= len(m.templates); i++ {
+		line := mutedStyle.Render("No template")
+		if i < len(m.templates) {
+			line = m.templates[i].Name
+			if m.templates[i].About != "" {
+				line += mutedStyle.Render(fmt.Sprintf(" – %s", m.templates[i].About))
+			}
+		}
+
+		if i == m.cursor {
+			b.WriteString(pickerSelectedStyle.Render("> ") + line)
+		} else {
+			b.WriteString("  " + line)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(helpStyle.Render("↑/↓: navigate • enter: choose • esc: cancel"))
+
+	return b.String()
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     CreatePROptions
+		template PRTemplate
+		want     CreatePROptions
+	}{
+		{
+			name:     "empty description",
+			opts:     CreatePROptions{Title: "Add login"},
+			template: PRTemplate{Body: "## Summary"},
+			want:     CreatePROptions{Title: "Add login", Description: "## Summary"},
+		},
+		{
+			name:     "description from commits is kept",
+			opts:     CreatePROptions{Title: "Add login", Description: "- Add form\n- Add route"},
+			template: PRTemplate{Body: "## Checklist\n\n- [ ] Tests"},
+			want:     CreatePROptions{Title: "Add login", Description: "## Checklist\n\n- [ ] Tests\n\n- Add form\n- Add route"},
+		},
+		{
+			name:     "template without body",
+			opts:     CreatePROptions{Description: "From commits"},
+			template: PRTemplate{Name: "Empty"},
+			want:     CreatePROptions{Description: "From commits"},
+		},
+		{
+			name:     "title prefix",
+			opts:     CreatePROptions{Title: "crash on start"},
+			template: PRTemplate{Title: "fix: "},
+			want:     CreatePROptions{Title: "fix: crash on start"},
+		},
+		{
+			name:     "title prefix already present",
+			opts:     CreatePROptions{Title: "fix: crash on start"},
+			template: PRTemplate{Title: "fix: "},
+			want:     CreatePROptions{Title: "fix: crash on start"},
+		},
+		{
+			name:     "labels are added once",
+			opts:     CreatePROptions{Labels: []string{"Bug"}},
+			template: PRTemplate{Labels: []string{"bug", "needs-review"}},
+			want:     CreatePROptions{Labels: []string{"Bug", "needs-review"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opts.withTemplate(tt.template)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("withTemplate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+)
+
+// reviewThread is a conversation anchored to a line of the diff. Gitea
+// shows all code comments on the same line as one conversation, replies
+// are just further comments on that line.
+type reviewThread struct {
+	path     string
+	oldLine  int
+	newLine  int
+	diffHunk string
+	comments []*gitea.PullReviewComment
+}
+
+// line returns the line the thread is anchored to, preferring the new
+// version of the file.
+func (t reviewThread) line() int {
+	if t.newLine != 0 {
+		return t.newLine
+	}
+	return t.oldLine
+}
+
+// resolver returns who resolved the thread, or "" if it is still open.
+func (t reviewThread) resolver() string {
+	for _, comment := range t.comments {
+		if comment.Resolver != nil {
+			return comment.Resolver.UserName
+		}
+	}
+	return ""
+}
+
+// groupThreads groups code comments by file and line, ordered by path and
+// line with the comments of each thread oldest first.
+func groupThreads(comments []*gitea.PullReviewComment) []reviewThread {
+	sorted := make([]*gitea.PullReviewComment, len(comments))
+	copy(sorted, comments)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Created.Before(sorted[j].Created)
+	})
+
+	type key struct {
+		path    string
+		oldLine uint64
+		newLine uint64
+	}
+	byKey := make(map[key]int)
+
+	var threads []reviewThread
+	for _, comment := range sorted {
+		k := key{comment.Path, comment.OldLineNum, comment.LineNum}
+		i, ok := byKey[k]
+		if !ok {
+			i = len(threads)
+			byKey[k] = i
+			threads = append(threads, reviewThread{
+				path:     comment.Path,
+				oldLine:  int(comment.OldLineNum),
+				newLine:  int(comment.LineNum),
+				diffHunk: comment.DiffHunk,
+			})
+		}
+		threads[i].comments = append(threads[i].comments, comment)
+	}
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		if threads[i].path != threads[j].path {
+			return threads[i].path < threads[j].path
+		}
+		return threads[i].line() < threads[j].line()
+	})
+	return threads
+}
+
+// timelineEntry is a review or comment in the conversation of a pull
+// request.
+type timelineEntry struct {
+	author string
+	action string
+	body   string
+	time   time.Time
+}
+
+// buildTimeline merges submitted reviews and conversation comments,
+// oldest first.
+func buildTimeline(reviews []*gitea.PullReview, comments []*gitea.Comment) []timelineEntry {
+	var entries []timelineEntry
+	for _, review := range reviews {
+		action := reviewAction(review)
+		if action == "" {
+			continue
+		}
+
+		author := ""
+		if review.Reviewer != nil {
+			author = review.Reviewer.UserName
+		}
+		entries = append(entries, timelineEntry{author: author, action: action, body: review.Body, time: review.Submitted})
+	}
+
+	for _, comment := range comments {
+		author := comment.OriginalAuthor
+		if comment.Poster != nil {
+			author = comment.Poster.UserName
+		}
+		entries = append(entries, timelineEntry{author: author, action: "commented", body: comment.Body, time: comment.Created})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time.Before(entries[j].time)
+	})
+	return entries
+}
+
+// reviewAction describes a submitted review, or returns "" for review
+// requests and pending reviews.
+func reviewAction(review *gitea.PullReview) string {
+	var action string
+	switch review.State {
+	case gitea.ReviewStateApproved:
+		action = "approved"
+	case gitea.ReviewStateRequestChanges:
+		action = "requested changes"
+	case gitea.ReviewStateComment:
+		action = "reviewed"
+	default:
+		return ""
+	}
+
+	if review.CodeCommentsCount == 1 {
+		action += " with 1 code comment"
+	} else if review.CodeCommentsCount > 1 {
+		action += fmt.Sprintf(" with %d code comments", review.CodeCommentsCount)
+	}
+	if review.Dismissed {
+		action += " (dismissed)"
+	}
+	return action
+}
+
+// hunkContext returns the last lines of a comment's diff hunk, which end at
+// the commented line.
+func hunkContext(diffHunk string, lines int) []string {
+	all := strings.Split(strings.TrimRight(diffHunk, "\n"), "\n")
+	// Skip the @@ header
+	if len(all) > 0 && strings.HasPrefix(all[0], "@@") {
+		all = all[1:]
+	}
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return all
+}